@@ -15,6 +15,8 @@ type Config struct {
 	Auth        AuthConfig
 	MinIO       MinIOConfig
 	Log         LogConfig
+	Scheduler   SchedulerConfig
+	Mail        MailConfig
 }
 
 type ServerConfig struct {
@@ -53,6 +55,24 @@ type LogConfig struct {
 	Format string // "json" or "text"
 }
 
+// SchedulerConfig controls background jobs. A zero interval disables the job.
+type SchedulerConfig struct {
+	PMInterval       time.Duration
+	SLAInterval      time.Duration
+	BriefInterval    time.Duration // How often to check for weekly briefs to send
+	BriefSendHour    int           // Hour (UTC) on Monday from which briefs are sent
+	SnapshotInterval time.Duration // How often to capture missing daily KPI snapshots
+}
+
+// MailConfig configures outgoing mail. Without an SMTP host mail is only logged.
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+}
+
 // Load reads configuration from environment variables
 func Load() *Config {
 	return &Config{
@@ -88,6 +108,20 @@ func Load() *Config {
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
+		Scheduler: SchedulerConfig{
+			PMInterval:       getEnvAsDuration("PM_EVAL_INTERVAL", 15*time.Minute),
+			SLAInterval:      getEnvAsDuration("SLA_SWEEP_INTERVAL", 5*time.Minute),
+			BriefInterval:    getEnvAsDuration("BRIEF_INTERVAL", 15*time.Minute),
+			BriefSendHour:    getEnvAsInt("BRIEF_SEND_HOUR", 6),
+			SnapshotInterval: getEnvAsDuration("SNAPSHOT_INTERVAL", time.Hour),
+		},
+		Mail: MailConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("MAIL_FROM", "IOI AMMS <no-reply@ioi-amms.local>"),
+		},
 	}
 }
 
@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"ioi-amms/internal/auth"
 	"ioi-amms/internal/middleware"
@@ -12,15 +17,25 @@ import (
 )
 
 type AnalyticsHandler struct {
-	repo *repository.AnalyticsRepository
+	repo   *repository.AnalyticsRepository
+	pmRepo *repository.PMRepository
 }
 
-func NewAnalyticsHandler(repo *repository.AnalyticsRepository) *AnalyticsHandler {
-	return &AnalyticsHandler{repo: repo}
+func NewAnalyticsHandler(repo *repository.AnalyticsRepository, pmRepo *repository.PMRepository) *AnalyticsHandler {
+	return &AnalyticsHandler{repo: repo, pmRepo: pmRepo}
 }
 
 func (h *AnalyticsHandler) RegisterRoutes(r chi.Router) {
 	r.Get("/analytics/dashboard", h.GetDashboardData)
+	r.Get("/analytics/reliability", h.GetReliability)
+	r.Get("/analytics/bad-actors", h.GetBadActors)
+	r.Get("/analytics/trends", h.GetTrends)
+
+	// Dashboard tile drill-downs
+	r.Get("/analytics/tiles/critical-down", h.ListCriticalDown)
+	r.Get("/analytics/tiles/overdue-pms", h.ListOverduePMs)
+	r.Get("/analytics/tiles/wallet-watch", h.ListWalletWatch)
+	r.Get("/analytics/tiles/data-health", h.ListDataHealth)
 }
 
 // GetDashboardData returns aggregated stats and alerts
@@ -33,13 +48,20 @@ func (h *AnalyticsHandler) GetDashboardData(w http.ResponseWriter, r *http.Reque
 
 	stats, err := h.repo.GetDashboardStats(r.Context(), claims.TenantID)
 	if err != nil {
-		internalError(w, err.Error())
+		analyticsError(w, err, "Failed to fetch dashboard stats")
+		return
+	}
+
+	overdue, err := h.overduePMs(r, claims.TenantID)
+	if err != nil {
+		analyticsError(w, err, "Failed to fetch dashboard stats")
 		return
 	}
+	stats.OverduePMs = len(overdue)
 
 	alerts, err := h.repo.GetTopAlerts(r.Context(), claims.TenantID)
 	if err != nil {
-		internalError(w, err.Error())
+		analyticsError(w, err, "Failed to fetch dashboard alerts")
 		return
 	}
 	// Ensure alerts is empty slice not null
@@ -54,3 +76,320 @@ func (h *AnalyticsHandler) GetDashboardData(w http.ResponseWriter, r *http.Reque
 
 	jsonResponse(w, http.StatusOK, resp)
 }
+
+// ListCriticalDown handles GET /analytics/tiles/critical-down
+func (h *AnalyticsHandler) ListCriticalDown(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	assets, err := h.repo.ListCriticalDown(r.Context(), claims.TenantID)
+	if err != nil {
+		analyticsError(w, err, "Failed to fetch critical down assets")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": assets})
+}
+
+// ListOverduePMs handles GET /analytics/tiles/overdue-pms
+func (h *AnalyticsHandler) ListOverduePMs(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	overdue, err := h.overduePMs(r, claims.TenantID)
+	if err != nil {
+		analyticsError(w, err, "Failed to fetch overdue PM schedules")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": overdue})
+}
+
+// ListWalletWatch handles GET /analytics/tiles/wallet-watch
+func (h *AnalyticsHandler) ListWalletWatch(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	entries, err := h.repo.ListWalletWatch(r.Context(), claims.TenantID)
+	if err != nil {
+		analyticsError(w, err, "Failed to fetch wallet watch")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": entries})
+}
+
+// ListDataHealth handles GET /analytics/tiles/data-health: in-service assets
+// whose meters have not been read within the data health window
+func (h *AnalyticsHandler) ListDataHealth(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	assets, err := h.repo.ListStaleMeters(r.Context(), claims.TenantID)
+	if err != nil {
+		analyticsError(w, err, "Failed to fetch data health")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data": assets,
+		"meta": map[string]interface{}{"windowDays": model.DataHealthWindowDays},
+	})
+}
+
+// overduePMs returns the tenant's active schedules on in-service assets that are
+// past at least one trigger, whether or not a work order has been raised yet
+func (h *AnalyticsHandler) overduePMs(r *http.Request, tenantID string) ([]PMScheduleResponse, error) {
+	schedules, err := h.pmRepo.ListForEvaluation(r.Context(), tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	overdue := []PMScheduleResponse{}
+	for _, s := range model.OverdueSchedules(schedules, now) {
+		overdue = append(overdue, newPMScheduleResponse(s, now))
+	}
+	return overdue, nil
+}
+
+// analyticsError logs an unexpected analytics failure and responds with a 500
+func analyticsError(w http.ResponseWriter, err error, message string) {
+	slog.Error(message, slog.String("error", err.Error()))
+	errorResponse(w, http.StatusInternalServerError, message)
+}
+
+// GetReliability handles GET /analytics/reliability: MTTR, MTBF and availability
+// per asset, asset model, location or org unit, with a summary over the whole scope
+func (h *AnalyticsHandler) GetReliability(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	period, ok := parsePeriod(w, r)
+	if !ok {
+		return
+	}
+
+	groupBy := r.URL.Query().Get("groupBy")
+	if groupBy == "" {
+		groupBy = model.ReliabilityByAsset
+	}
+	if !repository.IsValidReliabilityGroup(groupBy) {
+		validationError(w, "Invalid groupBy", map[string]string{"groupBy": "Must be asset, model, location or orgUnit"})
+		return
+	}
+
+	limit := parseIntParam(r, "limit", 50)
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+
+	params := model.ReliabilityParams{
+		TenantID:   claims.TenantID,
+		GroupBy:    groupBy,
+		LocationID: r.URL.Query().Get("locationId"),
+		OrgUnitID:  r.URL.Query().Get("orgUnitId"),
+		AssetID:    r.URL.Query().Get("assetId"),
+		From:       period.From,
+		To:         period.To,
+	}
+
+	groups, err := h.repo.GetReliability(r.Context(), params)
+	if err != nil {
+		analyticsError(w, err, "Failed to fetch reliability metrics")
+		return
+	}
+
+	// The summary covers every group, including those beyond the limit
+	summary := model.ReliabilityMetrics{Key: "all", Label: "All assets in scope"}
+	for _, g := range groups {
+		summary.Add(g)
+	}
+	summary.Compute()
+
+	total := len(groups)
+	if len(groups) > limit {
+		groups = groups[:limit]
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data":    groups,
+		"summary": summary,
+		"meta": map[string]interface{}{
+			"groupBy": groupBy,
+			"from":    period.From.Format("2006-01-02"),
+			"to":      period.To.AddDate(0, 0, -1).Format("2006-01-02"),
+			"total":   total,
+		},
+	})
+}
+
+// GetBadActors handles GET /analytics/bad-actors: the top N assets, asset models,
+// locations or org units by cost, labor, work orders or downtime over a period.
+// With ?format=csv the ranking is returned as a CSV attachment.
+func (h *AnalyticsHandler) GetBadActors(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	period, ok := parsePeriod(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	groupBy := q.Get("groupBy")
+	if groupBy == "" {
+		groupBy = model.ReliabilityByAsset
+	}
+	if !repository.IsValidReliabilityGroup(groupBy) {
+		validationError(w, "Invalid groupBy", map[string]string{"groupBy": "Must be asset, model, location or orgUnit"})
+		return
+	}
+
+	sortBy := q.Get("sortBy")
+	if sortBy == "" {
+		sortBy = model.BadActorByTotalCost
+	}
+	if !repository.IsValidBadActorSort(sortBy) {
+		validationError(w, "Invalid sortBy", map[string]string{
+			"sortBy": "Must be totalCost, laborHours, laborCost, partsCost, workOrders or downtimeHours",
+		})
+		return
+	}
+
+	format := q.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		validationError(w, "Invalid format", map[string]string{"format": "Must be json or csv"})
+		return
+	}
+
+	limit := parseIntParam(r, "limit", 10)
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	actors, total, err := h.repo.GetBadActors(r.Context(), model.BadActorParams{
+		TenantID: claims.TenantID,
+		GroupBy:  groupBy,
+		SortBy:   sortBy,
+		From:     period.From,
+		To:       period.To,
+		Limit:    limit,
+	})
+	if err != nil {
+		analyticsError(w, err, "Failed to fetch bad actor report")
+		return
+	}
+
+	from := period.From.Format("2006-01-02")
+	to := period.To.AddDate(0, 0, -1).Format("2006-01-02")
+
+	if format == "csv" {
+		writeBadActorsCSV(w, fmt.Sprintf("bad-actors_%s_%s_%s.csv", groupBy, from, to), actors)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data": actors,
+		"meta": map[string]interface{}{
+			"groupBy": groupBy,
+			"sortBy":  sortBy,
+			"from":    from,
+			"to":      to,
+			"limit":   limit,
+			"total":   total,
+		},
+	})
+}
+
+// writeBadActorsCSV writes the bad actor ranking as a CSV attachment
+func writeBadActorsCSV(w http.ResponseWriter, filename string, actors []model.BadActor) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"Rank", "Key", "Name", "Assets", "Work Orders",
+		"Labor Hours", "Labor Cost", "Parts Cost", "Total Cost", "Downtime Hours",
+	})
+	for _, b := range actors {
+		cw.Write([]string{
+			strconv.Itoa(b.Rank), b.Key, b.Label, strconv.Itoa(b.Assets), strconv.Itoa(b.WorkOrders),
+			money(b.LaborHours), money(b.LaborCost), money(b.PartsCost), money(b.TotalCost), money(b.DowntimeHours),
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		slog.Error("Failed to write bad actor CSV", slog.String("error", err.Error()))
+	}
+}
+
+// maxTrendDays limits the period of a daily trend
+const maxTrendDays = 366
+
+// GetTrends handles GET /analytics/trends: work orders opened vs closed, backlog,
+// PM compliance, spend and downtime per day, week or month, from the nightly snapshots
+func (h *AnalyticsHandler) GetTrends(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	period, ok := parsePeriod(w, r)
+	if !ok {
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	switch interval {
+	case "":
+		interval = model.TrendIntervalDay
+	case model.TrendIntervalDay, model.TrendIntervalWeek, model.TrendIntervalMonth:
+	default:
+		validationError(w, "Invalid interval", map[string]string{"interval": "Must be day, week or month"})
+		return
+	}
+	if interval == model.TrendIntervalDay && period.To.Sub(period.From) > maxTrendDays*24*time.Hour {
+		validationError(w, "Period too long", map[string]string{
+			"from": fmt.Sprintf("Daily trends cover at most %d days; use week or month", maxTrendDays),
+		})
+		return
+	}
+
+	buckets, err := h.repo.GetTrends(r.Context(), claims.TenantID, interval, period.From, period.To)
+	if err != nil {
+		analyticsError(w, err, "Failed to fetch trends")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data": buckets,
+		"meta": map[string]interface{}{
+			"interval": interval,
+			"from":     period.From.Format("2006-01-02"),
+			"to":       period.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		},
+	})
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -16,20 +17,30 @@ import (
 
 // AssetHandler handles asset-related HTTP requests
 type AssetHandler struct {
-	repo  *repository.AssetRepository
-	audit *service.AuditService
+	repo    *repository.AssetRepository
+	service *service.AssetService
 }
 
 // NewAssetHandler creates a new asset handler
-func NewAssetHandler(repo *repository.AssetRepository, audit *service.AuditService) *AssetHandler {
-	return &AssetHandler{repo: repo, audit: audit}
+func NewAssetHandler(repo *repository.AssetRepository, service *service.AssetService) *AssetHandler {
+	return &AssetHandler{repo: repo, service: service}
 }
 
 // RegisterRoutes registers asset routes
 func (h *AssetHandler) RegisterRoutes(r chi.Router) {
 	r.Get("/assets", h.List)
 	r.Post("/assets", h.Create)
+	r.Get("/assets/tree", h.Tree)
 	r.Get("/assets/{id}", h.Get)
+	r.Get("/assets/{id}/children", h.ListChildren)
+	r.Get("/assets/{id}/descendants", h.ListDescendants)
+	r.Get("/assets/{id}/ancestors", h.ListAncestors)
+	r.Get("/assets/{id}/tree", h.Subtree)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionAssetWrite))
+		r.Post("/assets/{id}/move", h.Move)
+	})
 	r.Put("/assets/{id}", h.Update)
 	r.Delete("/assets/{id}", h.Delete)
 }
@@ -147,22 +158,29 @@ func (h *AssetHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Specs:          req.Specs,
 	}
 
-	if err := h.repo.Create(r.Context(), asset); err != nil {
-		slog.Error("Failed to create asset", slog.String("error", err.Error()))
-		errorResponse(w, http.StatusInternalServerError, "Failed to create asset")
+	if err := h.service.Create(r.Context(), claims, asset); err != nil {
+		h.statusError(w, err, "Failed to create asset")
 		return
 	}
 
-	// Audit Log
-	h.audit.Log(r.Context(), claims.UserID, model.AuditActionCreate, model.AuditEntityAsset, asset.ID, map[string]interface{}{
-		"name": asset.Name,
-	})
-
 	jsonResponse(w, http.StatusCreated, asset)
 }
 
-// Update handles PUT /assets/{id}
+// UpdateAssetRequest represents the update asset request body; only Supervisors
+// and above may set isFieldVerified
+type UpdateAssetRequest struct {
+	CreateAssetRequest
+	IsFieldVerified *bool `json:"isFieldVerified"`
+}
+
+// Update handles PUT /assets/{id}. Status changes follow the asset lifecycle.
 func (h *AssetHandler) Update(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
 	id := chi.URLParam(r, "id")
 
 	// Verify asset exists
@@ -177,44 +195,61 @@ func (h *AssetHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req CreateAssetRequest
+	var req UpdateAssetRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		errorResponse(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if req.IsFieldVerified != nil && !service.CanVerifyAsset(claims.Role) {
+		forbiddenError(w, "Only supervisors can verify assets")
+		return
+	}
 
-	// Update fields only if provided (Partial Update)
+	// Update fields only if provided (Partial Update); status is audited on its own
+	from := existing.Status
+	var changed []string
 	if req.Name != "" {
 		existing.Name = req.Name
+		changed = append(changed, "name")
 	}
 	if req.ParentID != nil {
 		existing.ParentID = req.ParentID
+		changed = append(changed, "parentId")
 	}
 	if req.LocationID != nil {
 		existing.LocationID = req.LocationID
+		changed = append(changed, "locationId")
 	}
 	if req.OrgUnitID != nil {
 		existing.OrgUnitID = req.OrgUnitID
+		changed = append(changed, "orgUnitId")
 	}
 	if req.Status != "" {
 		existing.Status = req.Status
 	}
 	if req.IsFieldRelated != nil {
 		existing.IsFieldRelated = *req.IsFieldRelated
+		changed = append(changed, "isFieldRelated")
+	}
+	if req.IsFieldVerified != nil {
+		existing.IsFieldVerified = *req.IsFieldVerified
+		changed = append(changed, "isFieldVerified")
 	}
 	if req.Manufacturer != nil {
 		existing.Manufacturer = req.Manufacturer
+		changed = append(changed, "manufacturer")
 	}
 	if req.ModelNumber != nil {
 		existing.ModelNumber = req.ModelNumber
+		changed = append(changed, "modelNumber")
 	}
 	if req.Specs != nil {
 		existing.Specs = req.Specs
+		changed = append(changed, "specs")
 	}
 
-	if err := h.repo.Update(r.Context(), existing); err != nil {
-		slog.Error("Failed to update asset", slog.String("error", err.Error()))
-		errorResponse(w, http.StatusInternalServerError, "Failed to update asset")
+	if err := h.service.Update(r.Context(), claims, existing, from, changed); err != nil {
+		h.statusError(w, err, "Failed to update asset")
 		return
 	}
 
@@ -238,6 +273,205 @@ func (h *AssetHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ==================== HIERARCHY ====================
+
+// ListChildren handles GET /assets/{id}/children: the assets directly below
+func (h *AssetHandler) ListChildren(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	children, err := h.repo.ListDescendants(r.Context(), claims.TenantID, chi.URLParam(r, "id"), 1)
+	if err != nil {
+		hierarchyError(w, err, "Failed to fetch children")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": children})
+}
+
+// ListDescendants handles GET /assets/{id}/descendants: every asset below, depth
+// first, optionally limited to ?maxDepth levels
+func (h *AssetHandler) ListDescendants(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	maxDepth := parseIntParam(r, "maxDepth", 0)
+	if maxDepth < 0 {
+		validationError(w, "Invalid maxDepth", map[string]string{"maxDepth": "Must be 0 (unlimited) or more"})
+		return
+	}
+
+	descendants, err := h.repo.ListDescendants(r.Context(), claims.TenantID, chi.URLParam(r, "id"), maxDepth)
+	if err != nil {
+		hierarchyError(w, err, "Failed to fetch descendants")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": descendants})
+}
+
+// ListAncestors handles GET /assets/{id}/ancestors: the breadcrumb from the root
+// down to the asset's parent
+func (h *AssetHandler) ListAncestors(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ancestors, err := h.repo.ListAncestors(r.Context(), claims.TenantID, chi.URLParam(r, "id"))
+	if err != nil {
+		hierarchyError(w, err, "Failed to fetch ancestors")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": ancestors})
+}
+
+// Subtree handles GET /assets/{id}/tree: the asset with its descendants nested
+func (h *AssetHandler) Subtree(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	root, err := h.repo.FindByID(r.Context(), chi.URLParam(r, "id"))
+	if err == nil && root.TenantID != claims.TenantID {
+		err = repository.ErrAssetNotFound
+	}
+	if err != nil {
+		hierarchyError(w, err, "Failed to fetch asset tree")
+		return
+	}
+
+	descendants, err := h.repo.ListDescendants(r.Context(), claims.TenantID, root.ID, 0)
+	if err != nil {
+		hierarchyError(w, err, "Failed to fetch asset tree")
+		return
+	}
+
+	tree := model.NestAssets(append([]model.Asset{*root}, descendants...))
+	jsonResponse(w, http.StatusOK, tree[0])
+}
+
+// Tree handles GET /assets/tree: every asset of the tenant nested under its root
+func (h *AssetHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	assets, err := h.repo.ListAll(r.Context(), claims.TenantID)
+	if err != nil {
+		hierarchyError(w, err, "Failed to fetch asset tree")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": model.NestAssets(assets)})
+}
+
+// MoveAssetRequest relocates an asset with everything below it
+type MoveAssetRequest struct {
+	LocationID string `json:"locationId"`
+}
+
+// Move handles POST /assets/{id}/move: relocates the asset and all its descendants
+// in one transaction, recording a movement for each under a shared shipment reference
+func (h *AssetHandler) Move(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req MoveAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.LocationID == "" {
+		validationError(w, "Validation failed", map[string]string{"locationId": "Required"})
+		return
+	}
+
+	ref, moved, err := h.service.Move(r.Context(), claims, chi.URLParam(r, "id"), req.LocationID)
+	if err != nil {
+		h.statusError(w, err, "Failed to move asset")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"shipmentRef": ref,
+		"data":        moved,
+	})
+}
+
+// hierarchyError maps asset hierarchy read errors to HTTP responses
+func hierarchyError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, repository.ErrAssetNotFound) {
+		notFoundError(w, "Asset")
+		return
+	}
+	slog.Error(message, slog.String("error", err.Error()))
+	errorResponse(w, http.StatusInternalServerError, message)
+}
+
+// Asset lifecycle error codes
+const (
+	ErrCodeAssetTransition     = "ASSET_INVALID_TRANSITION"
+	ErrCodeAssetOpenWorkOrders = "ASSET_HAS_OPEN_WORK_ORDERS"
+	ErrCodeAssetNotVerified    = "ASSET_NOT_VERIFIED"
+	ErrCodeAssetStatusConflict = "ASSET_STATUS_CONFLICT"
+	ErrCodeAssetInTransit      = "ASSET_IN_TRANSIT"
+)
+
+// statusError maps asset create/update/move errors to HTTP responses; lifecycle
+// violations are 409s with a machine-readable code
+func (h *AssetHandler) statusError(w http.ResponseWriter, err error, message string) {
+	var te *service.AssetTransitionError
+	switch {
+	case errors.Is(err, repository.ErrAssetNotFound), errors.Is(err, service.ErrAssetForbidden):
+		notFoundError(w, "Asset")
+	case errors.Is(err, repository.ErrAssetParentInvalid):
+		validationError(w, "Invalid parent", map[string]string{"parentId": "Must be an asset of your tenant"})
+	case errors.Is(err, repository.ErrAssetHierarchyCycle):
+		validationError(w, "Invalid parent", map[string]string{"parentId": "Cannot be the asset itself or one of its descendants"})
+	case errors.Is(err, repository.ErrLocationNotFound):
+		validationError(w, "Invalid location", map[string]string{"locationId": "Must be a location of your tenant"})
+	case errors.Is(err, repository.ErrAssetInTransit):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeAssetInTransit,
+			"The asset or one of its sub-assets is in transit; receive it before moving it", nil)
+	case errors.Is(err, service.ErrInvalidAssetStatus):
+		validationError(w, "Invalid status", map[string]string{"status": "Must be Draft, Active, Down, Archived or Red_Tag"})
+	case errors.As(err, &te) && errors.Is(err, service.ErrAssetHasOpenWorkOrders):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeAssetOpenWorkOrders,
+			"Cannot archive an asset while it has open work orders",
+			map[string]interface{}{"from": te.From, "to": te.To, "openWorkOrders": te.OpenWorkOrders})
+	case errors.As(err, &te) && errors.Is(err, service.ErrAssetNotVerified):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeAssetNotVerified,
+			"The asset must be field verified before it is activated, or activated by a supervisor",
+			map[string]interface{}{"from": te.From, "to": te.To})
+	case errors.As(err, &te):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeAssetTransition,
+			"Cannot move asset from "+te.From+" to "+te.To,
+			map[string]interface{}{"from": te.From, "to": te.To, "allowed": te.Allowed})
+	case errors.Is(err, repository.ErrAssetStatusConflict):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeAssetStatusConflict,
+			"Asset status was changed by another user, please reload", nil)
+	default:
+		slog.Error(message, slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, message)
+	}
+}
+
 // parseIntParam helper
 func parseIntParam(r *http.Request, name string, defaultVal int) int {
 	val := r.URL.Query().Get(name)
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+	"ioi-amms/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// BriefHandler handles the Monday Morning Brief preview and subscriptions
+type BriefHandler struct {
+	repo    *repository.BriefRepository
+	service *service.BriefService
+}
+
+// NewBriefHandler creates a new brief handler
+func NewBriefHandler(repo *repository.BriefRepository, service *service.BriefService) *BriefHandler {
+	return &BriefHandler{repo: repo, service: service}
+}
+
+// RegisterRoutes registers brief routes; the brief is a tenant-wide report
+func (h *BriefHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionReportView))
+		r.Get("/briefs/preview", h.Preview)
+		r.Get("/briefs/subscription", h.GetSubscription)
+		r.Put("/briefs/subscription", h.UpdateSubscription)
+	})
+}
+
+// BriefSubscriptionRequest subscribes or unsubscribes the caller
+type BriefSubscriptionRequest struct {
+	Subscribed *bool `json:"subscribed"`
+}
+
+// Preview handles GET /briefs/preview: the brief PDF for the week containing
+// ?weekOf (YYYY-MM-DD), by default last week
+func (h *BriefHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	weekStart := model.BriefWeekStart(time.Now()).AddDate(0, 0, -7)
+	if v := r.URL.Query().Get("weekOf"); v != "" {
+		day, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			validationError(w, "Invalid weekOf", map[string]string{"weekOf": "Must be a date (YYYY-MM-DD)"})
+			return
+		}
+		weekStart = model.BriefWeekStart(day)
+	}
+
+	pdf, err := h.service.Render(r.Context(), claims.TenantID, weekStart, claims.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantNotFound) {
+			notFoundError(w, "Tenant")
+			return
+		}
+		slog.Error("Failed to render weekly brief", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to render brief")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", service.BriefFilename(weekStart)))
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
+
+// GetSubscription handles GET /briefs/subscription
+func (h *BriefHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	subscribed, err := h.repo.IsSubscribed(r.Context(), claims.UserID)
+	if err != nil {
+		slog.Error("Failed to fetch brief subscription", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch subscription")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"subscribed": subscribed})
+}
+
+// UpdateSubscription handles PUT /briefs/subscription
+func (h *BriefHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req BriefSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Subscribed == nil {
+		validationError(w, "Validation failed", map[string]string{"subscribed": "Required"})
+		return
+	}
+
+	var err error
+	if *req.Subscribed {
+		err = h.repo.Subscribe(r.Context(), claims.TenantID, claims.UserID)
+	} else {
+		err = h.repo.Unsubscribe(r.Context(), claims.UserID)
+	}
+	if err != nil {
+		slog.Error("Failed to update brief subscription", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to update subscription")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"subscribed": *req.Subscribed})
+}
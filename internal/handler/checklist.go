@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ChecklistHandler handles checklist template HTTP requests
+type ChecklistHandler struct {
+	repo *repository.ChecklistRepository
+}
+
+// NewChecklistHandler creates a new checklist template handler
+func NewChecklistHandler(repo *repository.ChecklistRepository) *ChecklistHandler {
+	return &ChecklistHandler{repo: repo}
+}
+
+// RegisterRoutes registers checklist template routes
+func (h *ChecklistHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/checklist-templates", h.List)
+	r.Get("/checklist-templates/{id}", h.Get)
+
+	// Templates define maintenance procedures, a supervisory task
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionWOAssign))
+		r.Post("/checklist-templates", h.Create)
+		r.Put("/checklist-templates/{id}", h.Update)
+		r.Delete("/checklist-templates/{id}", h.Delete)
+	})
+}
+
+// List handles GET /checklist-templates
+func (h *ChecklistHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	templates, err := h.repo.List(r.Context(), claims.TenantID)
+	if err != nil {
+		slog.Error("Failed to list checklist templates", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch checklist templates")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": templates})
+}
+
+// Get handles GET /checklist-templates/{id}
+func (h *ChecklistHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tmpl, ok := h.findForTenant(w, r)
+	if !ok {
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, tmpl)
+}
+
+// ChecklistTemplateRequest represents the create/update checklist template request body
+type ChecklistTemplateRequest struct {
+	Title       string                `json:"title"`
+	Description *string               `json:"description"`
+	Items       []model.ChecklistItem `json:"items"`
+}
+
+// Create handles POST /checklist-templates
+func (h *ChecklistHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req ChecklistTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Title == "" {
+		errorResponse(w, http.StatusBadRequest, "Title is required")
+		return
+	}
+
+	tmpl := &model.ChecklistTemplate{
+		TenantID:    claims.TenantID,
+		Title:       req.Title,
+		Description: req.Description,
+		Items:       req.Items,
+	}
+	if !validateChecklistItems(w, tmpl) {
+		return
+	}
+
+	if err := h.repo.Create(r.Context(), tmpl); err != nil {
+		slog.Error("Failed to create checklist template", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to create checklist template")
+		return
+	}
+
+	jsonResponse(w, http.StatusCreated, tmpl)
+}
+
+// Update handles PUT /checklist-templates/{id}
+func (h *ChecklistHandler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.findForTenant(w, r)
+	if !ok {
+		return
+	}
+
+	var req ChecklistTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Title != "" {
+		existing.Title = req.Title
+	}
+	if req.Description != nil {
+		existing.Description = req.Description
+	}
+	if req.Items != nil {
+		existing.Items = req.Items
+	}
+	if !validateChecklistItems(w, existing) {
+		return
+	}
+
+	if err := h.repo.Update(r.Context(), existing); err != nil {
+		slog.Error("Failed to update checklist template", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to update checklist template")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, existing)
+}
+
+// Delete handles DELETE /checklist-templates/{id}
+func (h *ChecklistHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tmpl, ok := h.findForTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), tmpl.ID); err != nil {
+		if err == repository.ErrChecklistTemplateInUse {
+			conflictError(w, "Checklist template is used by a PM schedule", nil)
+			return
+		}
+		slog.Error("Failed to delete checklist template", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to delete checklist template")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findForTenant loads the {id} template and hides templates owned by other tenants
+func (h *ChecklistHandler) findForTenant(w http.ResponseWriter, r *http.Request) (*model.ChecklistTemplate, bool) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return nil, false
+	}
+
+	tmpl, err := h.repo.FindByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		if err == repository.ErrChecklistTemplateNotFound {
+			errorResponse(w, http.StatusNotFound, "Checklist template not found")
+			return nil, false
+		}
+		slog.Error("Failed to get checklist template", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch checklist template")
+		return nil, false
+	}
+
+	if tmpl.TenantID != claims.TenantID {
+		errorResponse(w, http.StatusNotFound, "Checklist template not found")
+		return nil, false
+	}
+
+	return tmpl, true
+}
+
+// validateChecklistItems checks every item has a label and a known task type
+func validateChecklistItems(w http.ResponseWriter, t *model.ChecklistTemplate) bool {
+	if t.Items == nil {
+		t.Items = []model.ChecklistItem{}
+	}
+
+	for i, item := range t.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if item.Label == "" {
+			validationError(w, "Invalid checklist item", map[string]string{field: "Label is required"})
+			return false
+		}
+		if !model.IsValidTaskType(item.Type) {
+			validationError(w, "Invalid checklist item", map[string]string{
+				field: "Type must be one of pass_fail, numeric, text, photo",
+			})
+			return false
+		}
+		if item.MinValue != nil && item.MaxValue != nil && *item.MinValue > *item.MaxValue {
+			validationError(w, "Invalid checklist item", map[string]string{field: "minValue must not exceed maxValue"})
+			return false
+		}
+	}
+
+	return true
+}
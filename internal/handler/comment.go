@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/repository"
+	"ioi-amms/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CommentHandler handles work order comment and timeline HTTP requests
+type CommentHandler struct {
+	svc *service.CommentService
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(svc *service.CommentService) *CommentHandler {
+	return &CommentHandler{svc: svc}
+}
+
+// RegisterRoutes registers comment and timeline routes
+func (h *CommentHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/work-orders/{id}/comments", h.List)
+	r.Get("/work-orders/{id}/timeline", h.Timeline)
+	r.Get("/comments/mentions", h.Mentions)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionWOWrite))
+		r.Post("/work-orders/{id}/comments", h.Create)
+		r.Put("/work-orders/{id}/comments/{commentId}", h.Update)
+	})
+}
+
+// CommentRequest represents the create/update comment request body
+type CommentRequest struct {
+	ParentID *string  `json:"parentId"` // Create only
+	Body     string   `json:"body"`
+	Mentions []string `json:"mentions"`
+}
+
+// List handles GET /work-orders/{id}/comments
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	comments, err := h.svc.List(r.Context(), claims, chi.URLParam(r, "id"))
+	if err != nil {
+		h.commentError(w, err, "Failed to fetch comments")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": comments})
+}
+
+// Create handles POST /work-orders/{id}/comments
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	comment, err := h.svc.Create(r.Context(), claims, chi.URLParam(r, "id"), service.CommentInput{
+		ParentID: req.ParentID,
+		Body:     req.Body,
+		Mentions: req.Mentions,
+	})
+	if err != nil {
+		h.commentError(w, err, "Failed to add comment")
+		return
+	}
+
+	jsonResponse(w, http.StatusCreated, comment)
+}
+
+// Update handles PUT /work-orders/{id}/comments/{commentId}
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	comment, err := h.svc.Edit(r.Context(), claims, chi.URLParam(r, "id"), chi.URLParam(r, "commentId"), service.CommentInput{
+		Body:     req.Body,
+		Mentions: req.Mentions,
+	})
+	if err != nil {
+		h.commentError(w, err, "Failed to update comment")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, comment)
+}
+
+// Mentions handles GET /comments/mentions, the latest comments mentioning the caller
+func (h *CommentHandler) Mentions(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	limit := parseIntParam(r, "limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	comments, err := h.svc.ListMentions(r.Context(), claims, limit)
+	if err != nil {
+		h.commentError(w, err, "Failed to fetch mentions")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": comments})
+}
+
+// Timeline handles GET /work-orders/{id}/timeline
+func (h *CommentHandler) Timeline(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	entries, err := h.svc.Timeline(r.Context(), claims, chi.URLParam(r, "id"))
+	if err != nil {
+		h.commentError(w, err, "Failed to fetch timeline")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": entries})
+}
+
+// commentError maps comment service errors to HTTP responses
+func (h *CommentHandler) commentError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrWorkOrderNotFound), errors.Is(err, service.ErrWorkOrderForbidden):
+		notFoundError(w, "Work order")
+	case errors.Is(err, repository.ErrCommentNotFound):
+		notFoundError(w, "Comment")
+	case errors.Is(err, service.ErrCommentForbidden):
+		errorResponseWithCode(w, http.StatusForbidden, ErrCodeForbidden, "You can only edit your own comments", nil)
+	case errors.Is(err, service.ErrCommentEmpty):
+		validationError(w, "Invalid comment", map[string]string{"body": "Required"})
+	case errors.Is(err, service.ErrCommentTooLong):
+		validationError(w, "Invalid comment", map[string]string{"body": "Must be at most 4000 characters"})
+	case errors.Is(err, service.ErrCommentInvalidParent):
+		validationError(w, "Invalid comment", map[string]string{"parentId": "Comment not found on this work order"})
+	case errors.Is(err, service.ErrCommentInvalidMention):
+		validationError(w, "Invalid comment", map[string]string{"mentions": "Must be active users in your organization"})
+	default:
+		slog.Error(message, slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, message)
+	}
+}
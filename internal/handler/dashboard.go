@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DashboardHandler handles configurable KPI dashboards
+type DashboardHandler struct {
+	service *service.DashboardService
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(service *service.DashboardService) *DashboardHandler {
+	return &DashboardHandler{service: service}
+}
+
+// RegisterRoutes registers dashboard routes. Every user has a dashboard; widgets
+// are limited by the caller's role, and role defaults are tenant settings.
+func (h *DashboardHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/dashboards/widgets", h.ListWidgets)
+	r.Get("/dashboards/widgets/{id}/data", h.GetWidgetData)
+	r.Get("/dashboards/me", h.GetMine)
+	r.Put("/dashboards/me", h.SaveMine)
+	r.Delete("/dashboards/me", h.ResetMine)
+	r.Get("/dashboards/me/data", h.GetMyData)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionTenantSettings))
+		r.Get("/dashboards/roles/{role}", h.GetRole)
+		r.Put("/dashboards/roles/{role}", h.SaveRole)
+		r.Delete("/dashboards/roles/{role}", h.ResetRole)
+	})
+}
+
+// SaveDashboardRequest replaces a dashboard's widgets
+type SaveDashboardRequest struct {
+	Widgets []model.DashboardPlacement `json:"widgets"`
+}
+
+// dashboardScope returns the caller's dashboard scope
+func dashboardScope(w http.ResponseWriter, r *http.Request) (service.DashboardScope, bool) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return service.DashboardScope{}, false
+	}
+	return service.DashboardScope{TenantID: claims.TenantID, UserID: claims.UserID, Role: claims.Role}, true
+}
+
+// ListWidgets handles GET /dashboards/widgets: the widgets the caller may place
+func (h *DashboardHandler) ListWidgets(w http.ResponseWriter, r *http.Request) {
+	scope, ok := dashboardScope(w, r)
+	if !ok {
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": h.service.Widgets(scope.Role)})
+}
+
+// GetWidgetData handles GET /dashboards/widgets/{id}/data
+func (h *DashboardHandler) GetWidgetData(w http.ResponseWriter, r *http.Request) {
+	scope, ok := dashboardScope(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.WidgetData(r.Context(), scope, chi.URLParam(r, "id"))
+	if err != nil {
+		h.dashboardError(w, err, "Failed to load widget")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+// GetMine handles GET /dashboards/me: the caller's resolved layout
+func (h *DashboardHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	scope, ok := dashboardScope(w, r)
+	if !ok {
+		return
+	}
+
+	layout, err := h.service.ForUser(r.Context(), scope)
+	if err != nil {
+		h.dashboardError(w, err, "Failed to fetch dashboard")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, layout)
+}
+
+// SaveMine handles PUT /dashboards/me
+func (h *DashboardHandler) SaveMine(w http.ResponseWriter, r *http.Request) {
+	scope, ok := dashboardScope(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := decodeDashboardRequest(w, r)
+	if !ok {
+		return
+	}
+
+	layout, err := h.service.SaveForUser(r.Context(), scope, req.Widgets)
+	if err != nil {
+		h.dashboardError(w, err, "Failed to save dashboard")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, layout)
+}
+
+// ResetMine handles DELETE /dashboards/me: reverts to the role's dashboard
+func (h *DashboardHandler) ResetMine(w http.ResponseWriter, r *http.Request) {
+	scope, ok := dashboardScope(w, r)
+	if !ok {
+		return
+	}
+
+	layout, err := h.service.ResetUser(r.Context(), scope)
+	if err != nil {
+		h.dashboardError(w, err, "Failed to reset dashboard")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, layout)
+}
+
+// GetMyData handles GET /dashboards/me/data: the caller's layout with every
+// widget's data, keyed by widget ID
+func (h *DashboardHandler) GetMyData(w http.ResponseWriter, r *http.Request) {
+	scope, ok := dashboardScope(w, r)
+	if !ok {
+		return
+	}
+
+	layout, data, err := h.service.LayoutData(r.Context(), scope)
+	if err != nil {
+		h.dashboardError(w, err, "Failed to load dashboard")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"layout": layout,
+		"data":   data,
+	})
+}
+
+// GetRole handles GET /dashboards/roles/{role}: the tenant's dashboard for a role
+func (h *DashboardHandler) GetRole(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	layout, err := h.service.ForRole(r.Context(), claims.TenantID, chi.URLParam(r, "role"))
+	if err != nil {
+		h.dashboardError(w, err, "Failed to fetch dashboard")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, layout)
+}
+
+// SaveRole handles PUT /dashboards/roles/{role}
+func (h *DashboardHandler) SaveRole(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	req, ok := decodeDashboardRequest(w, r)
+	if !ok {
+		return
+	}
+
+	layout, err := h.service.SaveForRole(r.Context(), claims.TenantID, chi.URLParam(r, "role"), claims.UserID, req.Widgets)
+	if err != nil {
+		h.dashboardError(w, err, "Failed to save dashboard")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, layout)
+}
+
+// ResetRole handles DELETE /dashboards/roles/{role}: reverts to the built-in dashboard
+func (h *DashboardHandler) ResetRole(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	layout, err := h.service.ResetRole(r.Context(), claims.TenantID, chi.URLParam(r, "role"))
+	if err != nil {
+		h.dashboardError(w, err, "Failed to reset dashboard")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, layout)
+}
+
+// decodeDashboardRequest reads a layout body; widgets is required (it may be empty)
+func decodeDashboardRequest(w http.ResponseWriter, r *http.Request) (*SaveDashboardRequest, bool) {
+	var req SaveDashboardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return nil, false
+	}
+	if req.Widgets == nil {
+		validationError(w, "Validation failed", map[string]string{"widgets": "Required"})
+		return nil, false
+	}
+	return &req, true
+}
+
+// dashboardError maps dashboard errors to HTTP responses
+func (h *DashboardHandler) dashboardError(w http.ResponseWriter, err error, message string) {
+	var layoutErr *service.DashboardLayoutError
+	switch {
+	case errors.As(err, &layoutErr):
+		validationError(w, "Invalid dashboard layout", map[string]string{
+			"widgets[" + strconv.Itoa(layoutErr.Index) + "]": layoutErr.Message,
+		})
+	case errors.Is(err, service.ErrUnknownRole):
+		notFoundError(w, "Role")
+	case errors.Is(err, service.ErrUnknownWidget):
+		notFoundError(w, "Widget")
+	case errors.Is(err, service.ErrWidgetNotAllowed):
+		forbiddenError(w, "Widget is not available to your role")
+	default:
+		slog.Error(message, slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, message)
+	}
+}
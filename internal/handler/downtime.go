@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// defaultPeriodDays is the reporting window when no from date is given
+const defaultPeriodDays = 30
+
+// DowntimeHandler handles asset downtime and availability HTTP requests
+type DowntimeHandler struct {
+	repo   *repository.DowntimeRepository
+	assets *repository.AssetRepository
+}
+
+// NewDowntimeHandler creates a new downtime handler
+func NewDowntimeHandler(repo *repository.DowntimeRepository, assets *repository.AssetRepository) *DowntimeHandler {
+	return &DowntimeHandler{repo: repo, assets: assets}
+}
+
+// RegisterRoutes registers downtime routes
+func (h *DowntimeHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/assets/availability", h.Availability)
+	r.Get("/assets/{id}/downtime", h.AssetDowntime)
+}
+
+// AssetDowntime handles GET /assets/{id}/downtime: the asset's downtime events
+// in the period with its total downtime and availability
+func (h *DowntimeHandler) AssetDowntime(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	period, ok := parsePeriod(w, r)
+	if !ok {
+		return
+	}
+
+	asset, err := h.assets.FindByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil || asset.TenantID != claims.TenantID {
+		if err != nil && err != repository.ErrAssetNotFound {
+			slog.Error("Failed to get asset", slog.String("error", err.Error()))
+			errorResponse(w, http.StatusInternalServerError, "Failed to fetch downtime")
+			return
+		}
+		notFoundError(w, "Asset")
+		return
+	}
+
+	events, err := h.repo.ListByAsset(r.Context(), asset.ID, period)
+	if err != nil {
+		slog.Error("Failed to list downtime events", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch downtime")
+		return
+	}
+
+	summary := model.AssetAvailability{
+		AssetID:     asset.ID,
+		AssetName:   asset.Name,
+		Status:      asset.Status,
+		PeriodHours: period.Hours(),
+		Events:      len(events),
+	}
+	for _, e := range events {
+		summary.DowntimeHours += e.Hours
+	}
+	summary.AvailabilityPct = model.Availability(summary.PeriodHours, summary.DowntimeHours)
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data":    events,
+		"summary": summary,
+	})
+}
+
+// Availability handles GET /assets/availability: downtime and availability per
+// asset over the period, most downtime first
+func (h *DowntimeHandler) Availability(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	period, ok := parsePeriod(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.repo.Availability(r.Context(), claims.TenantID, period,
+		parseIntParam(r, "page", 1), parseIntParam(r, "limit", 10))
+	if err != nil {
+		slog.Error("Failed to compute availability", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch availability")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data": result.Data,
+		"meta": map[string]int{
+			"total":      result.Total,
+			"page":       result.Page,
+			"limit":      result.Limit,
+			"totalPages": result.TotalPages,
+		},
+	})
+}
+
+// parsePeriod reads the from/to query dates (YYYY-MM-DD, both inclusive, UTC).
+// to defaults to today and from to defaultPeriodDays before to.
+func parsePeriod(w http.ResponseWriter, r *http.Request) (model.Period, bool) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	to := today
+	if v := r.URL.Query().Get("to"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			validationError(w, "Invalid period", map[string]string{"to": "Expected YYYY-MM-DD"})
+			return model.Period{}, false
+		}
+		to = d
+	}
+
+	from := to.AddDate(0, 0, -defaultPeriodDays+1)
+	if v := r.URL.Query().Get("from"); v != "" {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			validationError(w, "Invalid period", map[string]string{"from": "Expected YYYY-MM-DD"})
+			return model.Period{}, false
+		}
+		from = d
+	}
+
+	if from.After(to) {
+		validationError(w, "Invalid period", map[string]string{"from": "Must not be after to"})
+		return model.Period{}, false
+	}
+
+	return model.Period{From: from, To: to.AddDate(0, 0, 1)}, true
+}
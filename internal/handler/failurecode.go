@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// FailureCodeHandler handles failure code catalog HTTP requests
+type FailureCodeHandler struct {
+	repo *repository.FailureCodeRepository
+}
+
+// NewFailureCodeHandler creates a new failure code handler
+func NewFailureCodeHandler(repo *repository.FailureCodeRepository) *FailureCodeHandler {
+	return &FailureCodeHandler{repo: repo}
+}
+
+// RegisterRoutes registers failure code routes
+func (h *FailureCodeHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/failure-codes", h.List)
+	r.Get("/failure-codes/{id}", h.Get)
+
+	// The catalog drives reliability reporting, so it is maintained by supervisors
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionWOAssign))
+		r.Post("/failure-codes", h.Create)
+		r.Put("/failure-codes/{id}", h.Update)
+		r.Delete("/failure-codes/{id}", h.Delete)
+	})
+}
+
+// List handles GET /failure-codes
+// Query: level, parentId, active=true, tree=true (nested Problem > Cause > Remedy)
+func (h *FailureCodeHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	q := r.URL.Query()
+	tree := q.Get("tree") == "true"
+	level, parentID := q.Get("level"), q.Get("parentId")
+	if tree {
+		level, parentID = "", ""
+	}
+
+	codes, err := h.repo.List(r.Context(), claims.TenantID, level, parentID, q.Get("active") == "true")
+	if err != nil {
+		slog.Error("Failed to list failure codes", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch failure codes")
+		return
+	}
+
+	if tree {
+		codes = buildFailureCodeTree(codes)
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": codes})
+}
+
+// buildFailureCodeTree nests codes under their parents and returns the Problems
+func buildFailureCodeTree(codes []model.FailureCode) []model.FailureCode {
+	children := make(map[string][]model.FailureCode)
+	for _, c := range codes {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var attach func(c model.FailureCode) model.FailureCode
+	attach = func(c model.FailureCode) model.FailureCode {
+		for _, child := range children[c.ID] {
+			c.Children = append(c.Children, attach(child))
+		}
+		return c
+	}
+
+	roots := []model.FailureCode{}
+	for _, c := range codes {
+		if c.ParentID == nil {
+			roots = append(roots, attach(c))
+		}
+	}
+	return roots
+}
+
+// Get handles GET /failure-codes/{id}
+func (h *FailureCodeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	fc, ok := h.findForTenant(w, r)
+	if !ok {
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, fc)
+}
+
+// FailureCodeRequest represents the create/update failure code request body
+type FailureCodeRequest struct {
+	ParentID    *string `json:"parentId"`
+	Level       string  `json:"level"`
+	Code        string  `json:"code"`
+	Description string  `json:"description"`
+	IsActive    *bool   `json:"isActive"`
+}
+
+// Create handles POST /failure-codes
+func (h *FailureCodeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req FailureCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Code == "" || req.Description == "" {
+		errorResponse(w, http.StatusBadRequest, "Code and Description are required")
+		return
+	}
+
+	parentLevel, ok := model.FailureParentLevel[req.Level]
+	if !ok {
+		validationError(w, "Invalid level", map[string]string{"level": "Must be Problem, Cause or Remedy"})
+		return
+	}
+
+	// Problems are roots; Causes sit under a Problem and Remedies under a Cause
+	if parentLevel == "" && req.ParentID != nil {
+		validationError(w, "Invalid parent", map[string]string{"parentId": "Problem codes cannot have a parent"})
+		return
+	}
+	if parentLevel != "" {
+		if req.ParentID == nil {
+			validationError(w, "Invalid parent", map[string]string{"parentId": "A " + parentLevel + " parent is required"})
+			return
+		}
+		parent, err := h.repo.FindByID(r.Context(), *req.ParentID)
+		if err != nil || parent.TenantID != claims.TenantID || parent.Level != parentLevel {
+			validationError(w, "Invalid parent", map[string]string{"parentId": "Must be a " + parentLevel + " code"})
+			return
+		}
+	}
+
+	fc := &model.FailureCode{
+		TenantID:    claims.TenantID,
+		ParentID:    req.ParentID,
+		Level:       req.Level,
+		Code:        req.Code,
+		Description: req.Description,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+
+	if err := h.repo.Create(r.Context(), fc); err != nil {
+		if err == repository.ErrFailureCodeExists {
+			conflictError(w, "Failure code already exists", map[string]interface{}{"code": fc.Code})
+			return
+		}
+		slog.Error("Failed to create failure code", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to create failure code")
+		return
+	}
+
+	jsonResponse(w, http.StatusCreated, fc)
+}
+
+// Update handles PUT /failure-codes/{id}. Level and parent cannot change.
+func (h *FailureCodeHandler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.findForTenant(w, r)
+	if !ok {
+		return
+	}
+
+	var req FailureCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Code != "" {
+		existing.Code = req.Code
+	}
+	if req.Description != "" {
+		existing.Description = req.Description
+	}
+	if req.IsActive != nil {
+		existing.IsActive = *req.IsActive
+	}
+
+	if err := h.repo.Update(r.Context(), existing); err != nil {
+		if err == repository.ErrFailureCodeExists {
+			conflictError(w, "Failure code already exists", map[string]interface{}{"code": existing.Code})
+			return
+		}
+		slog.Error("Failed to update failure code", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to update failure code")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, existing)
+}
+
+// Delete handles DELETE /failure-codes/{id}
+func (h *FailureCodeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	fc, ok := h.findForTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), fc.ID); err != nil {
+		if err == repository.ErrFailureCodeInUse {
+			conflictError(w, "Failure code has child codes or is used on work orders; deactivate it instead", nil)
+			return
+		}
+		slog.Error("Failed to delete failure code", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to delete failure code")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findForTenant loads the {id} failure code and hides codes owned by other tenants
+func (h *FailureCodeHandler) findForTenant(w http.ResponseWriter, r *http.Request) (*model.FailureCode, bool) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return nil, false
+	}
+
+	fc, err := h.repo.FindByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		if err == repository.ErrFailureCodeNotFound {
+			errorResponse(w, http.StatusNotFound, "Failure code not found")
+			return nil, false
+		}
+		slog.Error("Failed to get failure code", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch failure code")
+		return nil, false
+	}
+
+	if fc.TenantID != claims.TenantID {
+		errorResponse(w, http.StatusNotFound, "Failure code not found")
+		return nil, false
+	}
+
+	return fc, true
+}
@@ -99,12 +99,13 @@ func (h *InventoryHandler) GetPart(w http.ResponseWriter, r *http.Request) {
 
 // CreatePartRequest for creating a part
 type CreatePartRequest struct {
-	SKU           string  `json:"sku"`
-	Name          string  `json:"name"`
-	Category      *string `json:"category"`
-	UOM           string  `json:"uom"`
-	MinStockLevel float64 `json:"minStockLevel"`
-	IsStockItem   *bool   `json:"isStockItem"`
+	SKU           string   `json:"sku"`
+	Name          string   `json:"name"`
+	Category      *string  `json:"category"`
+	UOM           string   `json:"uom"`
+	MinStockLevel float64  `json:"minStockLevel"`
+	IsStockItem   *bool    `json:"isStockItem"`
+	UnitCost      *float64 `json:"unitCost"`
 }
 
 // CreatePart handles POST /parts
@@ -125,6 +126,10 @@ func (h *InventoryHandler) CreatePart(w http.ResponseWriter, r *http.Request) {
 		errorResponse(w, http.StatusBadRequest, "SKU and Name are required")
 		return
 	}
+	if req.UnitCost != nil && *req.UnitCost < 0 {
+		validationError(w, "Invalid part", map[string]string{"unitCost": "Cannot be negative"})
+		return
+	}
 
 	// Defaults
 	if req.UOM == "" {
@@ -143,6 +148,7 @@ func (h *InventoryHandler) CreatePart(w http.ResponseWriter, r *http.Request) {
 		UOM:           req.UOM,
 		MinStockLevel: req.MinStockLevel,
 		IsStockItem:   isStockItem,
+		UnitCost:      req.UnitCost,
 	}
 
 	if err := h.repo.CreatePart(r.Context(), part); err != nil {
@@ -191,6 +197,13 @@ func (h *InventoryHandler) UpdatePart(w http.ResponseWriter, r *http.Request) {
 	if req.IsStockItem != nil {
 		existing.IsStockItem = *req.IsStockItem
 	}
+	if req.UnitCost != nil {
+		if *req.UnitCost < 0 {
+			validationError(w, "Invalid part", map[string]string{"unitCost": "Cannot be negative"})
+			return
+		}
+		existing.UnitCost = req.UnitCost
+	}
 
 	if err := h.repo.UpdatePart(r.Context(), existing); err != nil {
 		slog.Error("Failed to update part", slog.String("error", err.Error()))
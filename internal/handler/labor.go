@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/repository"
+	"ioi-amms/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// LaborHandler handles work order labor log HTTP requests
+type LaborHandler struct {
+	svc *service.LaborService
+}
+
+// NewLaborHandler creates a new labor log handler
+func NewLaborHandler(svc *service.LaborService) *LaborHandler {
+	return &LaborHandler{svc: svc}
+}
+
+// RegisterRoutes registers labor log routes
+func (h *LaborHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/work-orders/{id}/labor", h.List)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionWOWrite))
+		r.Post("/work-orders/{id}/labor", h.Create)
+		r.Put("/work-orders/{id}/labor/{logId}", h.Update)
+	})
+}
+
+// LaborLogRequest represents the create/update labor log request body
+type LaborLogRequest struct {
+	UserID        string   `json:"userId"`
+	HoursSpent    *float64 `json:"hoursSpent"`
+	DatePerformed *string  `json:"datePerformed"` // YYYY-MM-DD
+	Comment       *string  `json:"comment"`
+}
+
+// List handles GET /work-orders/{id}/labor
+func (h *LaborHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	logs, summary, err := h.svc.List(r.Context(), claims, chi.URLParam(r, "id"))
+	if err != nil {
+		h.laborError(w, err, "Failed to fetch labor logs")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": logs, "summary": summary})
+}
+
+// Create handles POST /work-orders/{id}/labor
+func (h *LaborHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	entry, ok := decodeLaborEntry(w, r)
+	if !ok {
+		return
+	}
+
+	log, err := h.svc.Log(r.Context(), claims, chi.URLParam(r, "id"), entry)
+	if err != nil {
+		h.laborError(w, err, "Failed to log labor")
+		return
+	}
+
+	jsonResponse(w, http.StatusCreated, log)
+}
+
+// Update handles PUT /work-orders/{id}/labor/{logId}
+func (h *LaborHandler) Update(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	entry, ok := decodeLaborEntry(w, r)
+	if !ok {
+		return
+	}
+
+	log, err := h.svc.Edit(r.Context(), claims, chi.URLParam(r, "id"), chi.URLParam(r, "logId"), entry)
+	if err != nil {
+		h.laborError(w, err, "Failed to update labor log")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, log)
+}
+
+// decodeLaborEntry parses the request body into a labor entry
+func decodeLaborEntry(w http.ResponseWriter, r *http.Request) (service.LaborEntry, bool) {
+	var req LaborLogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return service.LaborEntry{}, false
+	}
+
+	entry := service.LaborEntry{
+		UserID:     req.UserID,
+		HoursSpent: req.HoursSpent,
+		Comment:    req.Comment,
+	}
+	if req.DatePerformed != nil {
+		d, err := time.Parse("2006-01-02", *req.DatePerformed)
+		if err != nil {
+			validationError(w, "Invalid date", map[string]string{"datePerformed": "Expected YYYY-MM-DD"})
+			return entry, false
+		}
+		entry.DatePerformed = &d
+	}
+
+	return entry, true
+}
+
+// laborError maps labor service errors to HTTP responses
+func (h *LaborHandler) laborError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrWorkOrderNotFound), errors.Is(err, service.ErrWorkOrderForbidden):
+		notFoundError(w, "Work order")
+	case errors.Is(err, repository.ErrLaborLogNotFound):
+		notFoundError(w, "Labor log")
+	case errors.Is(err, service.ErrLaborForbidden):
+		errorResponseWithCode(w, http.StatusForbidden, ErrCodeForbidden, "You can only log or edit your own labor", nil)
+	case errors.Is(err, service.ErrLaborWorkOrderClosed):
+		conflictError(w, "Labor cannot be logged on a closed or cancelled work order", nil)
+	case errors.Is(err, service.ErrLaborInvalidHours):
+		validationError(w, "Invalid labor entry", map[string]string{"hoursSpent": "Must be greater than 0 and at most 24"})
+	case errors.Is(err, service.ErrLaborFutureDate):
+		validationError(w, "Invalid labor entry", map[string]string{"datePerformed": "Cannot be in the future"})
+	case errors.Is(err, service.ErrLaborInvalidUser):
+		validationError(w, "Invalid labor entry", map[string]string{"userId": "User not found"})
+	default:
+		slog.Error(message, slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, message)
+	}
+}
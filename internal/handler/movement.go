@@ -0,0 +1,414 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+	"ioi-amms/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MovementHandler handles asset transfers (chain of custody)
+type MovementHandler struct {
+	repo    *repository.MovementRepository
+	service *service.MovementService
+}
+
+// NewMovementHandler creates a new asset movement handler
+func NewMovementHandler(repo *repository.MovementRepository, service *service.MovementService) *MovementHandler {
+	return &MovementHandler{repo: repo, service: service}
+}
+
+// RegisterRoutes registers asset movement routes. Anyone who can see assets may
+// request, receive and dispute transfers; the service gates approvals by role.
+func (h *MovementHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionAssetRead))
+		r.Post("/asset-movements", h.CreateTransfer)
+		r.Get("/asset-movements", h.List)
+		r.Get("/asset-movements/shipments", h.ListShipments)
+		r.Get("/asset-movements/shipments/{ref}", h.GetShipment)
+		r.Post("/asset-movements/shipments/{ref}/approve", h.Approve)
+		r.Post("/asset-movements/shipments/{ref}/reject", h.Reject)
+		r.Get("/asset-movements/shipments/{ref}/gate-pass", h.GatePass)
+		r.Post("/asset-movements/shipments/{ref}/receive", h.ReceiveShipment)
+		r.Get("/asset-movements/{id}", h.Get)
+		r.Post("/asset-movements/{id}/receive", h.Receive)
+		r.Post("/asset-movements/{id}/dispute", h.Dispute)
+	})
+}
+
+// CreateTransferRequest ships assets to a location
+type CreateTransferRequest struct {
+	AssetIDs     []string `json:"assetIds"`
+	ToLocationID string   `json:"toLocationId"`
+	TransferType string   `json:"transferType"`
+	Notes        *string  `json:"notes"`
+}
+
+// ReviewShipmentRequest is the optional note on an approval or rejection
+type ReviewShipmentRequest struct {
+	Note *string `json:"note"`
+}
+
+// ReceiveShipmentRequest lists the shipment's assets that did not arrive
+type ReceiveShipmentRequest struct {
+	MissingAssetIDs []string `json:"missingAssetIds"`
+	Note            *string  `json:"note"`
+}
+
+// DisputeMovementRequest explains a dispute
+type DisputeMovementRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CreateTransfer handles POST /asset-movements
+func (h *MovementHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	errs := map[string]string{}
+	if len(req.AssetIDs) == 0 {
+		errs["assetIds"] = "At least one asset is required"
+	}
+	if req.ToLocationID == "" {
+		errs["toLocationId"] = "Required"
+	}
+	if len(errs) > 0 {
+		validationError(w, "Validation failed", errs)
+		return
+	}
+	if req.TransferType == "" {
+		req.TransferType = model.TransferTypeTransfer
+	}
+
+	ref, movements, err := h.service.CreateTransfer(r.Context(), claims, model.TransferRequest{
+		AssetIDs:     req.AssetIDs,
+		ToLocationID: req.ToLocationID,
+		TransferType: req.TransferType,
+		Notes:        req.Notes,
+	})
+	if err != nil {
+		h.movementError(w, err, "Failed to create transfer")
+		return
+	}
+
+	jsonResponse(w, http.StatusCreated, map[string]interface{}{
+		"shipmentRef": ref,
+		"status":      movements[0].Status,
+		"data":        movements,
+	})
+}
+
+// List handles GET /asset-movements
+func (h *MovementHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	result, err := h.repo.List(r.Context(), model.AssetMovementListParams{
+		TenantID:    claims.TenantID,
+		Status:      r.URL.Query()["status"],
+		ShipmentRef: r.URL.Query().Get("shipmentRef"),
+		AssetID:     r.URL.Query().Get("assetId"),
+		Page:        parseIntParam(r, "page", 1),
+		Limit:       parseIntParam(r, "limit", 20),
+	})
+	if err != nil {
+		slog.Error("Failed to list asset movements", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch asset movements")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data": result.Data,
+		"meta": map[string]int{
+			"total":      result.Total,
+			"page":       result.Page,
+			"limit":      result.Limit,
+			"totalPages": result.TotalPages,
+		},
+	})
+}
+
+// ListShipments handles GET /asset-movements/shipments: shipments with a movement
+// in one of the given statuses, In_Transit by default
+func (h *MovementHandler) ListShipments(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	statuses := r.URL.Query()["status"]
+	if len(statuses) == 0 {
+		statuses = []string{model.MovementStatusInTransit}
+	}
+	page := parseIntParam(r, "page", 1)
+	limit := parseIntParam(r, "limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	shipments, total, err := h.repo.ListShipments(r.Context(), claims.TenantID, statuses, page, limit)
+	if err != nil {
+		slog.Error("Failed to list shipments", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch shipments")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data": shipments,
+		"meta": map[string]int{
+			"total":      total,
+			"page":       page,
+			"limit":      limit,
+			"totalPages": (total + limit - 1) / limit,
+		},
+	})
+}
+
+// GetShipment handles GET /asset-movements/shipments/{ref}
+func (h *MovementHandler) GetShipment(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ref := chi.URLParam(r, "ref")
+	movements, err := h.repo.ListShipment(r.Context(), claims.TenantID, ref)
+	if err != nil {
+		h.movementError(w, err, "Failed to fetch shipment")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"shipmentRef": ref,
+		"data":        movements,
+	})
+}
+
+// Approve handles POST /asset-movements/shipments/{ref}/approve
+func (h *MovementHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, true)
+}
+
+// Reject handles POST /asset-movements/shipments/{ref}/reject
+func (h *MovementHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, false)
+}
+
+func (h *MovementHandler) review(w http.ResponseWriter, r *http.Request, approve bool) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// The note is optional, and so is the body
+	var req ReviewShipmentRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	ref := chi.URLParam(r, "ref")
+	movements, err := h.service.ReviewShipment(r.Context(), claims, ref, approve, req.Note)
+	if err != nil {
+		h.movementError(w, err, "Failed to review shipment")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"shipmentRef": ref,
+		"data":        movements,
+	})
+}
+
+// GatePass handles GET /asset-movements/shipments/{ref}/gate-pass: the shipment's
+// printable manifest with its master QR
+func (h *MovementHandler) GatePass(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ref := chi.URLParam(r, "ref")
+	pdf, err := h.service.GatePass(r.Context(), claims, ref)
+	if err != nil {
+		h.movementError(w, err, "Failed to render gate pass")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "gate-pass_"+ref+".pdf"))
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
+
+// ReceiveShipment handles POST /asset-movements/shipments/{ref}/receive: receives
+// everything in transit at once, reporting the items that were not received
+func (h *MovementHandler) ReceiveShipment(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Nothing missing, nothing to say: the body is optional
+	var req ReceiveShipmentRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	receipt, err := h.service.ReceiveShipment(r.Context(), claims, chi.URLParam(r, "ref"), req.MissingAssetIDs, req.Note)
+	if err != nil {
+		h.movementError(w, err, "Failed to receive shipment")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, receipt)
+}
+
+// Get handles GET /asset-movements/{id}
+func (h *MovementHandler) Get(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	m, err := h.service.Get(r.Context(), claims, chi.URLParam(r, "id"))
+	if err != nil {
+		h.movementError(w, err, "Failed to fetch asset movement")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, m)
+}
+
+// Receive handles POST /asset-movements/{id}/receive
+func (h *MovementHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	m, err := h.service.Receive(r.Context(), claims, chi.URLParam(r, "id"))
+	if err != nil {
+		h.movementError(w, err, "Failed to receive asset movement")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, m)
+}
+
+// Dispute handles POST /asset-movements/{id}/dispute
+func (h *MovementHandler) Dispute(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req DisputeMovementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		validationError(w, "Validation failed", map[string]string{"reason": "Required"})
+		return
+	}
+
+	m, err := h.service.Dispute(r.Context(), claims, chi.URLParam(r, "id"), req.Reason)
+	if err != nil {
+		h.movementError(w, err, "Failed to dispute asset movement")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, m)
+}
+
+// Asset movement error codes
+const (
+	ErrCodeMovementTransition     = "MOVEMENT_INVALID_TRANSITION"
+	ErrCodeMovementStatusConflict = "MOVEMENT_STATUS_CONFLICT"
+	ErrCodeNothingToTransfer      = "TRANSFER_NOTHING_TO_MOVE"
+	ErrCodeShipmentNotApproved    = "SHIPMENT_NOT_APPROVED"
+	ErrCodeShipmentNotInTransit   = "SHIPMENT_NOT_IN_TRANSIT"
+)
+
+// movementError maps asset movement errors to HTTP responses
+func (h *MovementHandler) movementError(w http.ResponseWriter, err error, message string) {
+	var te *service.MovementTransitionError
+	switch {
+	case errors.Is(err, repository.ErrMovementNotFound), errors.Is(err, service.ErrMovementTenant):
+		notFoundError(w, "Asset movement")
+	case errors.Is(err, repository.ErrShipmentNotFound):
+		notFoundError(w, "Shipment")
+	case errors.Is(err, repository.ErrAssetNotFound):
+		validationError(w, "Invalid assets", map[string]string{"assetIds": "Must all be assets of your tenant"})
+	case errors.Is(err, repository.ErrLocationNotFound):
+		validationError(w, "Invalid location", map[string]string{"toLocationId": "Must be a location of your tenant"})
+	case errors.Is(err, service.ErrInvalidTransferType):
+		validationError(w, "Invalid transfer type", map[string]string{"transferType": "Must be Transfer, Repair or Loan"})
+	case errors.Is(err, repository.ErrAssetInTransit):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeAssetInTransit,
+			"An asset or one of its sub-assets already has an open transfer", nil)
+	case errors.Is(err, repository.ErrNothingToTransfer):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeNothingToTransfer,
+			"The assets are already at the destination", nil)
+	case errors.Is(err, service.ErrShipmentNotApproved):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeShipmentNotApproved,
+			"The shipment has no approved items; approve it before printing its gate pass", nil)
+	case errors.Is(err, repository.ErrShipmentNotInTransit):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeShipmentNotInTransit,
+			"Nothing in the shipment is in transit", nil)
+	case errors.Is(err, service.ErrMovementForbidden):
+		forbiddenError(w, "Your role cannot make this change to the transfer")
+	case errors.As(err, &te):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeMovementTransition,
+			"Cannot move transfer from "+te.From+" to "+te.To,
+			map[string]interface{}{"from": te.From, "to": te.To, "allowed": te.Allowed})
+	case errors.Is(err, repository.ErrMovementStatusConflict):
+		errorResponseWithCode(w, http.StatusConflict, ErrCodeMovementStatusConflict,
+			"Transfer status was changed by another user, please reload", nil)
+	default:
+		slog.Error(message, slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NotificationHandler handles the caller's in-app notifications
+type NotificationHandler struct {
+	repo *repository.NotificationRepository
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(repo *repository.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{repo: repo}
+}
+
+// RegisterRoutes registers notification routes
+func (h *NotificationHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/notifications", h.List)
+	r.Post("/notifications/read-all", h.MarkAllRead)
+	r.Patch("/notifications/{id}/read", h.MarkRead)
+}
+
+// List handles GET /notifications
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	limit := parseIntParam(r, "limit", 20)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	notifications, unread, err := h.repo.List(r.Context(), claims.UserID, r.URL.Query().Get("unread") == "true", limit)
+	if err != nil {
+		slog.Error("Failed to list notifications", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch notifications")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": notifications, "unread": unread})
+}
+
+// MarkRead handles PATCH /notifications/{id}/read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	if err := h.repo.MarkRead(r.Context(), claims.UserID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, repository.ErrNotificationNotFound) {
+			notFoundError(w, "Notification")
+			return
+		}
+		slog.Error("Failed to mark notification read", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to update notification")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllRead handles POST /notifications/read-all
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	count, err := h.repo.MarkAllRead(r.Context(), claims.UserID)
+	if err != nil {
+		slog.Error("Failed to mark notifications read", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to update notifications")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]int{"updated": count})
+}
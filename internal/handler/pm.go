@@ -0,0 +1,318 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+	"ioi-amms/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PMHandler handles preventive maintenance schedule HTTP requests
+type PMHandler struct {
+	repo       *repository.PMRepository
+	assets     *repository.AssetRepository
+	checklists *repository.ChecklistRepository
+	svc        *service.PMService
+}
+
+// NewPMHandler creates a new PM schedule handler
+func NewPMHandler(repo *repository.PMRepository, assets *repository.AssetRepository, checklists *repository.ChecklistRepository, svc *service.PMService) *PMHandler {
+	return &PMHandler{repo: repo, assets: assets, checklists: checklists, svc: svc}
+}
+
+// RegisterRoutes registers PM schedule routes
+func (h *PMHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/pm-schedules", h.List)
+	r.Get("/pm-schedules/{id}", h.Get)
+
+	// Maintenance planning is a supervisory task
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionWOAssign))
+		r.Post("/pm-schedules", h.Create)
+		r.Post("/pm-schedules/evaluate", h.Evaluate)
+		r.Put("/pm-schedules/{id}", h.Update)
+		r.Delete("/pm-schedules/{id}", h.Delete)
+	})
+}
+
+// PMScheduleResponse is a PM schedule with its current due status
+type PMScheduleResponse struct {
+	model.PMSchedule
+	DueStatus model.PMDueStatus `json:"dueStatus"`
+}
+
+func newPMScheduleResponse(s model.PMSchedule, now time.Time) PMScheduleResponse {
+	return PMScheduleResponse{PMSchedule: s, DueStatus: s.CheckDue(now)}
+}
+
+// List handles GET /pm-schedules
+func (h *PMHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	schedules, err := h.repo.List(r.Context(), claims.TenantID, r.URL.Query().Get("assetId"))
+	if err != nil {
+		slog.Error("Failed to list PM schedules", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch PM schedules")
+		return
+	}
+
+	now := time.Now()
+	resp := make([]PMScheduleResponse, 0, len(schedules))
+	for _, s := range schedules {
+		resp = append(resp, newPMScheduleResponse(s, now))
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": resp})
+}
+
+// Get handles GET /pm-schedules/{id}
+func (h *PMHandler) Get(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := h.findForTenant(w, r)
+	if !ok {
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, newPMScheduleResponse(*schedule, time.Now()))
+}
+
+// PMScheduleRequest represents the create/update PM schedule request body
+type PMScheduleRequest struct {
+	AssetID                 string   `json:"assetId"`
+	ChecklistTemplateID     *string  `json:"checklistTemplateId"`
+	Title                   string   `json:"title"`
+	IntervalDays            *int     `json:"intervalDays"`
+	IntervalRunHours        *int     `json:"intervalRunHours"`
+	IntervalOdometerKm      *int     `json:"intervalOdometerKm"`
+	LastPerformedDate       *string  `json:"lastPerformedDate"` // YYYY-MM-DD
+	LastPerformedRunHours   *float64 `json:"lastPerformedRunHours"`
+	LastPerformedOdometerKm *float64 `json:"lastPerformedOdometerKm"`
+	SuppressPMIDs           []string `json:"suppressPmIds"`
+	IsActive                *bool    `json:"isActive"`
+}
+
+// Create handles POST /pm-schedules
+func (h *PMHandler) Create(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req PMScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.AssetID == "" || req.Title == "" {
+		errorResponse(w, http.StatusBadRequest, "AssetID and Title are required")
+		return
+	}
+
+	asset, err := h.assets.FindByID(r.Context(), req.AssetID)
+	if err != nil || asset.TenantID != claims.TenantID {
+		validationError(w, "Invalid asset", map[string]string{"assetId": "Asset not found"})
+		return
+	}
+
+	schedule := &model.PMSchedule{
+		AssetID:  req.AssetID,
+		IsActive: true,
+	}
+	if !h.applyRequest(w, schedule, &req) || !h.validateReferences(w, r, claims.TenantID, schedule) {
+		return
+	}
+
+	if err := h.repo.Create(r.Context(), schedule); err != nil {
+		slog.Error("Failed to create PM schedule", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to create PM schedule")
+		return
+	}
+
+	created, err := h.repo.FindByID(r.Context(), schedule.ID)
+	if err != nil {
+		slog.Error("Failed to reload PM schedule", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to create PM schedule")
+		return
+	}
+
+	jsonResponse(w, http.StatusCreated, newPMScheduleResponse(*created, time.Now()))
+}
+
+// Update handles PUT /pm-schedules/{id}
+func (h *PMHandler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.findForTenant(w, r)
+	if !ok {
+		return
+	}
+
+	var req PMScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if !h.applyRequest(w, existing, &req) || !h.validateReferences(w, r, existing.TenantID, existing) {
+		return
+	}
+
+	if err := h.repo.Update(r.Context(), existing); err != nil {
+		slog.Error("Failed to update PM schedule", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to update PM schedule")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, newPMScheduleResponse(*existing, time.Now()))
+}
+
+// Delete handles DELETE /pm-schedules/{id}
+func (h *PMHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := h.findForTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), schedule.ID); err != nil {
+		slog.Error("Failed to delete PM schedule", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to delete PM schedule")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Evaluate handles POST /pm-schedules/evaluate, running the evaluator for the caller's tenant now
+func (h *PMHandler) Evaluate(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	created, err := h.svc.Evaluate(r.Context(), claims.TenantID)
+	if err != nil {
+		slog.Error("Failed to evaluate PM schedules", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to evaluate PM schedules")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"created": created})
+}
+
+// findForTenant loads the {id} schedule and hides schedules owned by other tenants
+func (h *PMHandler) findForTenant(w http.ResponseWriter, r *http.Request) (*model.PMSchedule, bool) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return nil, false
+	}
+
+	schedule, err := h.repo.FindByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		if err == repository.ErrPMScheduleNotFound {
+			errorResponse(w, http.StatusNotFound, "PM schedule not found")
+			return nil, false
+		}
+		slog.Error("Failed to get PM schedule", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch PM schedule")
+		return nil, false
+	}
+
+	if schedule.TenantID != claims.TenantID {
+		errorResponse(w, http.StatusNotFound, "PM schedule not found")
+		return nil, false
+	}
+
+	return schedule, true
+}
+
+// applyRequest copies provided fields onto the schedule and validates the result
+func (h *PMHandler) applyRequest(w http.ResponseWriter, s *model.PMSchedule, req *PMScheduleRequest) bool {
+	if req.Title != "" {
+		s.Title = req.Title
+	}
+	if req.ChecklistTemplateID != nil {
+		s.ChecklistTemplateID = req.ChecklistTemplateID
+	}
+	if req.IntervalDays != nil {
+		s.IntervalDays = req.IntervalDays
+	}
+	if req.IntervalRunHours != nil {
+		s.IntervalRunHours = req.IntervalRunHours
+	}
+	if req.IntervalOdometerKm != nil {
+		s.IntervalOdometerKm = req.IntervalOdometerKm
+	}
+	if req.LastPerformedDate != nil {
+		d, err := time.Parse("2006-01-02", *req.LastPerformedDate)
+		if err != nil {
+			validationError(w, "Invalid date", map[string]string{"lastPerformedDate": "Expected YYYY-MM-DD"})
+			return false
+		}
+		s.LastPerformedDate = &d
+	}
+	if req.LastPerformedRunHours != nil {
+		s.LastPerformedRunHours = req.LastPerformedRunHours
+	}
+	if req.LastPerformedOdometerKm != nil {
+		s.LastPerformedOdometerKm = req.LastPerformedOdometerKm
+	}
+	if req.SuppressPMIDs != nil {
+		s.SuppressPMIDs = req.SuppressPMIDs
+	}
+	if s.SuppressPMIDs == nil {
+		s.SuppressPMIDs = []string{}
+	}
+	if req.IsActive != nil {
+		s.IsActive = *req.IsActive
+	}
+
+	if !positive(s.IntervalDays) && !positive(s.IntervalRunHours) && !positive(s.IntervalOdometerKm) {
+		validationError(w, "At least one interval is required", map[string]string{
+			"intervalDays": "Provide intervalDays, intervalRunHours or intervalOdometerKm",
+		})
+		return false
+	}
+
+	return true
+}
+
+// validateReferences ensures the checklist template belongs to the tenant and the
+// schedule only suppresses other schedules on the same asset
+func (h *PMHandler) validateReferences(w http.ResponseWriter, r *http.Request, tenantID string, s *model.PMSchedule) bool {
+	if s.ChecklistTemplateID != nil {
+		tmpl, err := h.checklists.FindByID(r.Context(), *s.ChecklistTemplateID)
+		if err != nil || tmpl.TenantID != tenantID {
+			validationError(w, "Invalid checklist template", map[string]string{"checklistTemplateId": "Checklist template not found"})
+			return false
+		}
+	}
+
+	for _, id := range s.SuppressPMIDs {
+		if id == s.ID {
+			validationError(w, "Invalid suppression list", map[string]string{"suppressPmIds": "A schedule cannot suppress itself"})
+			return false
+		}
+		target, err := h.repo.FindByID(r.Context(), id)
+		if err != nil || target.AssetID != s.AssetID {
+			validationError(w, "Invalid suppression list", map[string]string{"suppressPmIds": "Schedule " + id + " not found on this asset"})
+			return false
+		}
+	}
+	return true
+}
+
+func positive(v *int) bool {
+	return v != nil && *v > 0
+}
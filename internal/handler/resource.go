@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/repository"
+	"ioi-amms/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ResourceHandler handles parts consumed on work orders
+type ResourceHandler struct {
+	svc *service.InventoryService
+}
+
+// NewResourceHandler creates a new work order parts handler
+func NewResourceHandler(svc *service.InventoryService) *ResourceHandler {
+	return &ResourceHandler{svc: svc}
+}
+
+// RegisterRoutes registers work order parts routes
+func (h *ResourceHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/work-orders/{id}/parts", h.List)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionWOWrite))
+		r.Post("/work-orders/{id}/parts", h.Consume)
+	})
+}
+
+// ConsumePartRequest represents the consume part request body
+type ConsumePartRequest struct {
+	PartID     string  `json:"partId"`
+	Quantity   float64 `json:"quantity"`
+	Source     string  `json:"source"`     // Wallet (default) or Warehouse
+	LocationID *string `json:"locationId"` // Required for Warehouse
+}
+
+// List handles GET /work-orders/{id}/parts
+func (h *ResourceHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	logs, err := h.svc.ListConsumption(r.Context(), claims, chi.URLParam(r, "id"))
+	if err != nil {
+		h.consumeError(w, err, "Failed to fetch consumed parts")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": logs})
+}
+
+// Consume handles POST /work-orders/{id}/parts
+func (h *ResourceHandler) Consume(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req ConsumePartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.PartID == "" {
+		errorResponse(w, http.StatusBadRequest, "PartID is required")
+		return
+	}
+
+	log, err := h.svc.ConsumePart(r.Context(), claims, chi.URLParam(r, "id"), service.PartConsumption{
+		PartID:     req.PartID,
+		Quantity:   req.Quantity,
+		Source:     req.Source,
+		LocationID: req.LocationID,
+	})
+	if err != nil {
+		h.consumeError(w, err, "Failed to consume part")
+		return
+	}
+
+	jsonResponse(w, http.StatusCreated, log)
+}
+
+// consumeError maps part consumption errors to HTTP responses
+func (h *ResourceHandler) consumeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrWorkOrderNotFound), errors.Is(err, service.ErrWorkOrderForbidden):
+		notFoundError(w, "Work order")
+	case errors.Is(err, repository.ErrPartNotFound), errors.Is(err, service.ErrPartForbidden):
+		validationError(w, "Invalid part", map[string]string{"partId": "Part not found"})
+	case errors.Is(err, service.ErrInvalidPartSource):
+		validationError(w, "Invalid source", map[string]string{"source": "Must be Wallet or Warehouse"})
+	case errors.Is(err, service.ErrPartLocationRequired):
+		validationError(w, "Location required", map[string]string{"locationId": "Required when source is Warehouse"})
+	case errors.Is(err, service.ErrInvalidPartQuantity):
+		validationError(w, "Invalid quantity", map[string]string{"quantity": "Must be greater than 0"})
+	case errors.Is(err, service.ErrPartsWorkOrderNotOpen):
+		conflictError(w, "Parts cannot be consumed on a closed or cancelled work order", nil)
+	case errors.Is(err, repository.ErrInsufficientStock):
+		conflictError(w, "Not enough stock to consume this quantity", nil)
+	default:
+		slog.Error(message, slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, message)
+	}
+}
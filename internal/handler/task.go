@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/repository"
+	"ioi-amms/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TaskHandler handles work order task HTTP requests
+type TaskHandler struct {
+	svc *service.TaskService
+}
+
+// NewTaskHandler creates a new work order task handler
+func NewTaskHandler(svc *service.TaskService) *TaskHandler {
+	return &TaskHandler{svc: svc}
+}
+
+// RegisterRoutes registers work order task routes
+func (h *TaskHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/work-orders/{id}/tasks", h.List)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionWOWrite))
+		r.Put("/work-orders/{id}/tasks/{taskId}", h.Complete)
+	})
+}
+
+// List handles GET /work-orders/{id}/tasks
+func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	tasks, err := h.svc.List(r.Context(), claims, chi.URLParam(r, "id"))
+	if err != nil {
+		h.taskError(w, err, "Failed to fetch tasks")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"data": tasks})
+}
+
+// Complete handles PUT /work-orders/{id}/tasks/{taskId}
+func (h *TaskHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req service.TaskResult
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	task, err := h.svc.Complete(r.Context(), claims, chi.URLParam(r, "id"), chi.URLParam(r, "taskId"), req)
+	if err != nil {
+		h.taskError(w, err, "Failed to record task result")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, task)
+}
+
+// taskError maps task service errors to HTTP responses
+func (h *TaskHandler) taskError(w http.ResponseWriter, err error, message string) {
+	var resultErr *service.TaskResultError
+	switch {
+	case errors.Is(err, repository.ErrWorkOrderNotFound), errors.Is(err, service.ErrWorkOrderForbidden):
+		notFoundError(w, "Work order")
+	case errors.Is(err, repository.ErrTaskNotFound):
+		notFoundError(w, "Task")
+	case errors.Is(err, service.ErrTaskWorkOrderNotActive):
+		conflictError(w, "Tasks can only be recorded while the work order is In_Progress", nil)
+	case errors.As(err, &resultErr):
+		validationError(w, "Invalid task result", map[string]string{resultErr.Field: resultErr.Message})
+	default:
+		slog.Error(message, slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, message)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
 	"ioi-amms/internal/repository"
 
 	"github.com/go-chi/chi/v5"
@@ -81,6 +82,20 @@ func (h *TenantHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if raw, ok := req.Settings["slaPolicies"]; ok {
+		if msg := validateSLAPolicies(raw); msg != "" {
+			validationError(w, "Invalid settings", map[string]string{"slaPolicies": msg})
+			return
+		}
+	}
+
+	if raw, ok := req.Settings["walletWatchThreshold"]; ok {
+		if v, isNum := raw.(float64); !isNum || v < 0 {
+			validationError(w, "Invalid settings", map[string]string{"walletWatchThreshold": "Must be a non-negative number"})
+			return
+		}
+	}
+
 	updated, err := h.repo.UpdateSettings(r.Context(), claims.TenantID, req.Settings)
 	if err != nil {
 		slog.Error("Failed to update tenant settings", slog.String("error", err.Error()))
@@ -96,3 +111,31 @@ func (h *TenantHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
 
 	jsonResponse(w, http.StatusOK, resp)
 }
+
+// validateSLAPolicies checks the slaPolicies setting maps known priorities to
+// positive hour targets, returning a message describing the first problem
+func validateSLAPolicies(raw interface{}) string {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return "Invalid format"
+	}
+	var policies map[string]repository.SLAPolicy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return "Expected {\"<priority>\": {\"respondHours\": number, \"resolveHours\": number}}"
+	}
+
+	for priority, p := range policies {
+		switch priority {
+		case model.WOPriorityLow, model.WOPriorityMedium, model.WOPriorityHigh, model.WOPriorityCritical:
+		default:
+			return "Unknown priority " + priority
+		}
+		if p.RespondHours <= 0 || p.ResolveHours <= 0 {
+			return priority + ": respondHours and resolveHours must be greater than 0"
+		}
+		if p.RespondHours > p.ResolveHours {
+			return priority + ": respondHours cannot exceed resolveHours"
+		}
+	}
+	return ""
+}
@@ -40,10 +40,11 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		FullName  string  `json:"fullName"`
-		Role      string  `json:"role"`
-		IsActive  bool    `json:"isActive"`
-		OrgUnitID *string `json:"orgUnitId"`
+		FullName   string   `json:"fullName"`
+		Role       string   `json:"role"`
+		IsActive   bool     `json:"isActive"`
+		OrgUnitID  *string  `json:"orgUnitId"`
+		HourlyRate *float64 `json:"hourlyRate"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -78,6 +79,13 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	}
 	existingUser.IsActive = req.IsActive
 	existingUser.OrgUnitID = req.OrgUnitID // Can be nil
+	if req.HourlyRate != nil {
+		if *req.HourlyRate < 0 {
+			badRequest(w, "Hourly rate cannot be negative", nil)
+			return
+		}
+		existingUser.HourlyRate = req.HourlyRate
+	}
 
 	if err := h.repo.Update(r.Context(), existingUser); err != nil {
 		internalError(w, err.Error())
@@ -2,33 +2,45 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"ioi-amms/internal/middleware"
 	"ioi-amms/internal/model"
 	"ioi-amms/internal/repository"
+	"ioi-amms/internal/service"
 
 	"github.com/go-chi/chi/v5"
 )
 
 // WorkOrderHandler handles work order HTTP requests
 type WorkOrderHandler struct {
-	repo *repository.WorkOrderRepository
+	repo       *repository.WorkOrderRepository
+	checklists *repository.ChecklistRepository
+	svc        *service.WorkOrderService
 }
 
 // NewWorkOrderHandler creates a new work order handler
-func NewWorkOrderHandler(repo *repository.WorkOrderRepository) *WorkOrderHandler {
-	return &WorkOrderHandler{repo: repo}
+func NewWorkOrderHandler(repo *repository.WorkOrderRepository, checklists *repository.ChecklistRepository, svc *service.WorkOrderService) *WorkOrderHandler {
+	return &WorkOrderHandler{repo: repo, checklists: checklists, svc: svc}
 }
 
 // RegisterRoutes registers work order routes
 func (h *WorkOrderHandler) RegisterRoutes(r chi.Router) {
 	r.Get("/work-orders", h.List)
+	r.Get("/work-orders/mine", h.ListMine)
 	r.Post("/work-orders", h.Create)
 	r.Get("/work-orders/{id}", h.Get)
 	r.Put("/work-orders/{id}", h.Update)
 	r.Patch("/work-orders/{id}/status", h.UpdateStatus)
+
+	// Dispatch is a supervisory task
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequirePermission(middleware.PermissionWOAssign))
+		r.Get("/work-orders/workload", h.Workload)
+		r.Patch("/work-orders/{id}/assignment", h.Assign)
+	})
 }
 
 // List handles GET /work-orders
@@ -49,7 +61,40 @@ func (h *WorkOrderHandler) List(w http.ResponseWriter, r *http.Request) {
 		Page:     parseIntParam(r, "page", 1),
 		Limit:    parseIntParam(r, "limit", 10),
 	}
+	params.AssignedUserID = r.URL.Query().Get("assignedUserId")
+	params.Overdue = r.URL.Query().Get("overdue") == "true"
+
+	h.list(w, r, params)
+}
+
+// ListMine handles GET /work-orders/mine, the caller's open assigned jobs.
+// Pass status to include closed or cancelled work orders.
+func (h *WorkOrderHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	params := model.WorkOrderListParams{
+		TenantID:       claims.TenantID,
+		Status:         r.URL.Query()["status"],
+		Priority:       r.URL.Query()["priority"],
+		AssetID:        r.URL.Query().Get("assetId"),
+		AssignedUserID: claims.UserID,
+		SortBy:         r.URL.Query().Get("sortBy"),
+		SortDir:        r.URL.Query().Get("sortDir"),
+		Page:           parseIntParam(r, "page", 1),
+		Limit:          parseIntParam(r, "limit", 10),
+	}
+	params.OpenOnly = len(params.Status) == 0
+	params.Overdue = r.URL.Query().Get("overdue") == "true"
+
+	h.list(w, r, params)
+}
 
+// list writes a paginated work order list response
+func (h *WorkOrderHandler) list(w http.ResponseWriter, r *http.Request, params model.WorkOrderListParams) {
 	result, err := h.repo.List(r.Context(), params)
 	if err != nil {
 		slog.Error("Failed to list work orders", slog.String("error", err.Error()))
@@ -72,6 +117,12 @@ func (h *WorkOrderHandler) List(w http.ResponseWriter, r *http.Request) {
 
 // Get handles GET /work-orders/{id}
 func (h *WorkOrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
 	id := chi.URLParam(r, "id")
 
 	wo, err := h.repo.FindByID(r.Context(), id)
@@ -84,17 +135,30 @@ func (h *WorkOrderHandler) Get(w http.ResponseWriter, r *http.Request) {
 		errorResponse(w, http.StatusInternalServerError, "Failed to fetch work order")
 		return
 	}
+	if wo.TenantID != claims.TenantID {
+		errorResponse(w, http.StatusNotFound, "Work order not found")
+		return
+	}
+
+	// Defect follow-ups raised from this work order's failed tasks
+	wo.FollowUps, err = h.repo.ListFollowUps(r.Context(), wo.ID)
+	if err != nil {
+		slog.Error("Failed to list follow-up work orders", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch work order")
+		return
+	}
 
 	jsonResponse(w, http.StatusOK, wo)
 }
 
 // CreateWorkOrderRequest represents the create work order request body (v1.1)
 type CreateWorkOrderRequest struct {
-	AssetID     *string `json:"assetId"`
-	Priority    string  `json:"priority"`
-	Origin      string  `json:"origin"`
-	Title       string  `json:"title"`
-	Description *string `json:"description"`
+	AssetID             *string `json:"assetId"`
+	ChecklistTemplateID *string `json:"checklistTemplateId"`
+	Priority            string  `json:"priority"`
+	Origin              string  `json:"origin"`
+	Title               string  `json:"title"`
+	Description         *string `json:"description"`
 }
 
 // Create handles POST /work-orders
@@ -121,19 +185,33 @@ func (h *WorkOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.ChecklistTemplateID != nil {
+		tmpl, err := h.checklists.FindByID(r.Context(), *req.ChecklistTemplateID)
+		if err != nil || tmpl.TenantID != claims.TenantID {
+			validationError(w, "Invalid checklist template", map[string]string{"checklistTemplateId": "Checklist template not found"})
+			return
+		}
+	}
+
 	// Set defaults
 	if req.Priority == "" {
 		req.Priority = model.WOPriorityMedium
 	}
+	if !model.IsValidWOPriority(req.Priority) {
+		invalidPriority(w)
+		return
+	}
 
 	wo := &model.WorkOrder{
-		TenantID:    claims.TenantID,
-		AssetID:     req.AssetID,
-		Status:      model.WOStatusRequested, // v1.1 default status
-		Priority:    req.Priority,
-		Origin:      req.Origin,
-		Title:       req.Title,
-		Description: req.Description,
+		TenantID:            claims.TenantID,
+		AssetID:             req.AssetID,
+		RequestedByUserID:   &claims.UserID,
+		ChecklistTemplateID: req.ChecklistTemplateID,
+		Status:              model.WOStatusRequested, // v1.1 default status
+		Priority:            req.Priority,
+		Origin:              req.Origin,
+		Title:               req.Title,
+		Description:         req.Description,
 	}
 
 	if err := h.repo.Create(r.Context(), wo); err != nil {
@@ -152,8 +230,15 @@ type UpdateWorkOrderRequest struct {
 	Description *string `json:"description"`
 }
 
-// Update handles PUT /work-orders/{id}
+// Update handles PUT /work-orders/{id}. The status is not editable here; use
+// PATCH /work-orders/{id}/status.
 func (h *WorkOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
 	id := chi.URLParam(r, "id")
 
 	existing, err := h.repo.FindByID(r.Context(), id)
@@ -166,6 +251,10 @@ func (h *WorkOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
 		errorResponse(w, http.StatusInternalServerError, "Failed to update work order")
 		return
 	}
+	if existing.TenantID != claims.TenantID {
+		errorResponse(w, http.StatusNotFound, "Work order not found")
+		return
+	}
 
 	var req UpdateWorkOrderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -174,6 +263,10 @@ func (h *WorkOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.Priority != "" {
+		if !model.IsValidWOPriority(req.Priority) {
+			invalidPriority(w)
+			return
+		}
 		existing.Priority = req.Priority
 	}
 	if req.Title != "" {
@@ -181,7 +274,7 @@ func (h *WorkOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	existing.Description = req.Description
 
-	if err := h.repo.Update(r.Context(), existing); err != nil {
+	if err := h.repo.Update(r.Context(), existing, claims.UserID); err != nil {
 		slog.Error("Failed to update work order", slog.String("error", err.Error()))
 		errorResponse(w, http.StatusInternalServerError, "Failed to update work order")
 		return
@@ -190,13 +283,27 @@ func (h *WorkOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
 	jsonResponse(w, http.StatusOK, existing)
 }
 
+// invalidPriority rejects an unknown priority, which would leave the work order
+// without SLA deadlines
+func invalidPriority(w http.ResponseWriter) {
+	errorResponseWithCode(w, http.StatusUnprocessableEntity, ErrCodeValidation, "Invalid priority",
+		map[string]interface{}{"priority": "Must be Low, Medium, High or Critical"})
+}
+
 // UpdateStatusRequest represents status update request
 type UpdateStatusRequest struct {
-	Status string `json:"status"`
+	Status   string            `json:"status"`
+	Closeout *model.WOCloseout `json:"closeout"` // Closed only
 }
 
 // UpdateStatus handles PATCH /work-orders/{id}/status
 func (h *WorkOrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
 	id := chi.URLParam(r, "id")
 
 	var req UpdateStatusRequest
@@ -210,11 +317,115 @@ func (h *WorkOrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := h.repo.UpdateStatus(r.Context(), id, req.Status); err != nil {
-		slog.Error("Failed to update work order status", slog.String("error", err.Error()))
-		errorResponse(w, http.StatusInternalServerError, "Failed to update status")
+	wo, err := h.svc.Transition(r.Context(), claims, id, req.Status, req.Closeout)
+	if err != nil {
+		h.transitionError(w, err)
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, wo)
+}
+
+// AssignRequest represents the assignment request body; a null userId unassigns
+type AssignRequest struct {
+	UserID *string `json:"userId"`
+}
+
+// Assign handles PATCH /work-orders/{id}/assignment
+func (h *WorkOrderHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	jsonResponse(w, http.StatusOK, map[string]string{"status": req.Status})
+	var req AssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	wo, err := h.svc.Assign(r.Context(), claims, chi.URLParam(r, "id"), req.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrWorkOrderNotFound), errors.Is(err, service.ErrWorkOrderForbidden):
+			notFoundError(w, "Work order")
+		case errors.Is(err, service.ErrInvalidAssignee):
+			validationError(w, "Invalid assignee", map[string]string{"userId": "Must be an active user in your organization"})
+		case errors.Is(err, service.ErrWorkOrderFinished):
+			conflictError(w, "Closed or cancelled work orders cannot be reassigned", nil)
+		default:
+			slog.Error("Failed to assign work order", slog.String("error", err.Error()))
+			errorResponse(w, http.StatusInternalServerError, "Failed to assign work order")
+		}
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, wo)
+}
+
+// Workload handles GET /work-orders/workload, open work per technician for
+// dispatch balancing. Pass role to include other roles (default Technician).
+func (h *WorkOrderHandler) Workload(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetUserFromContext(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	roles := r.URL.Query()["role"]
+	if len(roles) == 0 {
+		roles = []string{middleware.RoleTechnician}
+	}
+
+	workload, err := h.repo.Workload(r.Context(), claims.TenantID, roles)
+	if err != nil {
+		slog.Error("Failed to get technician workload", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch workload")
+		return
+	}
+
+	unassigned, err := h.repo.CountUnassignedOpen(r.Context(), claims.TenantID)
+	if err != nil {
+		slog.Error("Failed to count unassigned work orders", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to fetch workload")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, map[string]interface{}{
+		"data":       workload,
+		"unassigned": unassigned,
+	})
+}
+
+// transitionError maps work order lifecycle errors to HTTP responses
+func (h *WorkOrderHandler) transitionError(w http.ResponseWriter, err error) {
+	var te *service.TransitionError
+	var openTasks *service.OpenTasksError
+	var closeoutErr *service.CloseoutError
+	switch {
+	case errors.Is(err, repository.ErrWorkOrderNotFound), errors.Is(err, service.ErrWorkOrderForbidden):
+		notFoundError(w, "Work order")
+	case errors.Is(err, service.ErrInvalidStatus):
+		badRequest(w, "Unknown work order status", nil)
+	case errors.As(err, &te) && errors.Is(err, service.ErrTransitionDenied):
+		errorResponseWithCode(w, http.StatusForbidden, ErrCodeForbidden,
+			"You are not permitted to move this work order from "+te.From+" to "+te.To,
+			map[string]interface{}{"from": te.From, "to": te.To})
+	case errors.As(err, &closeoutErr):
+		validationError(w, "Invalid close-out", map[string]string{closeoutErr.Field: closeoutErr.Message})
+	case errors.Is(err, service.ErrLaborRequired):
+		conflictError(w, "Log labor on this work order before closing it", map[string]interface{}{"smartCloseout": true})
+	case errors.As(err, &openTasks):
+		conflictError(w, "Complete all mandatory tasks before marking work complete",
+			map[string]interface{}{"openMandatoryTasks": openTasks.Open})
+	case errors.As(err, &te):
+		conflictError(w, "Cannot move work order from "+te.From+" to "+te.To,
+			map[string]interface{}{"from": te.From, "to": te.To, "allowed": te.Allowed})
+	case errors.Is(err, repository.ErrWorkOrderStatusConflict):
+		conflictError(w, "Work order status was changed by another user, please reload", nil)
+	default:
+		slog.Error("Failed to update work order status", slog.String("error", err.Error()))
+		errorResponse(w, http.StatusInternalServerError, "Failed to update status")
+	}
 }
@@ -0,0 +1,111 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+)
+
+func TestBuildMessage(t *testing.T) {
+	pdf := bytes.Repeat([]byte("%PDF-1.3\x00\xff"), 40)
+	msg := Message{
+		To:      []string{"ana@example.com", "ben@example.com"},
+		Subject: "Monday Morning Brief – Plant 1",
+		Body:    "Hello Ana,\n\nAttached is the brief.\n",
+		Attachments: []Attachment{
+			{Filename: "monday-brief_2026-10-05.pdf", ContentType: "application/pdf", Data: pdf},
+		},
+	}
+
+	data, err := buildMessage("AMMS <noreply@amms.example.com>", msg)
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+
+	m, err := netmail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+
+	headers := []struct{ name, want string }{
+		{"From", "AMMS <noreply@amms.example.com>"},
+		{"To", "ana@example.com, ben@example.com"},
+		{"MIME-Version", "1.0"},
+	}
+	for _, h := range headers {
+		if got := m.Header.Get(h.name); got != h.want {
+			t.Errorf("%s = %q, want %q", h.name, got, h.want)
+		}
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, msg.Subject)
+	}
+	if id := m.Header.Get("Message-ID"); !strings.HasSuffix(id, "@amms.example.com>") {
+		t.Errorf("Message-ID = %q, want sender's domain", id)
+	}
+	if _, err := m.Header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q (%v), want multipart/mixed", mediaType, err)
+	}
+
+	parts := []struct {
+		contentType string
+		filename    string
+		data        []byte
+	}{
+		{"text/plain; charset=utf-8", "", []byte(msg.Body)},
+		{"application/pdf", "monday-brief_2026-10-05.pdf", pdf},
+	}
+
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	for i, want := range parts {
+		p, err := mr.NextRawPart()
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+		if got := p.Header.Get("Content-Type"); got != want.contentType {
+			t.Errorf("part %d: Content-Type = %q, want %q", i, got, want.contentType)
+		}
+		if got := p.Header.Get("Content-Transfer-Encoding"); got != "base64" {
+			t.Errorf("part %d: Content-Transfer-Encoding = %q, want base64", i, got)
+		}
+		if want.filename != "" {
+			disposition, dp, err := mime.ParseMediaType(p.Header.Get("Content-Disposition"))
+			if err != nil || disposition != "attachment" || dp["filename"] != want.filename {
+				t.Errorf("part %d: Content-Disposition = %q, want attachment %s", i, p.Header.Get("Content-Disposition"), want.filename)
+			}
+		}
+
+		raw, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+		for _, line := range strings.Split(strings.TrimRight(string(raw), "\r\n"), "\r\n") {
+			if len(line) > 76 {
+				t.Errorf("part %d: base64 line of %d characters", i, len(line))
+				break
+			}
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(raw), "\r\n", ""))
+		if err != nil {
+			t.Fatalf("part %d: decode: %v", i, err)
+		}
+		if !bytes.Equal(decoded, want.data) {
+			t.Errorf("part %d: decoded content does not match", i)
+		}
+	}
+
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("expected exactly %d parts, got more (%v)", len(parts), err)
+	}
+}
@@ -31,6 +31,7 @@ const (
 	PermissionWORead         = "wo:read"
 	PermissionWOWrite        = "wo:write"
 	PermissionWOAssign       = "wo:assign"
+	PermissionWOApprove      = "wo:approve"
 	PermissionWOClose        = "wo:close"
 	PermissionInventoryRead  = "inventory:read"
 	PermissionInventoryWrite = "inventory:write"
@@ -49,7 +50,7 @@ var RolePermissions = map[string][]string{
 	},
 	RoleSupervisor: {
 		PermissionAssetRead, PermissionAssetWrite,
-		PermissionWORead, PermissionWOWrite, PermissionWOAssign, PermissionWOClose,
+		PermissionWORead, PermissionWOWrite, PermissionWOAssign, PermissionWOApprove, PermissionWOClose,
 		PermissionReportView,
 	},
 	RoleStoreman: {
@@ -58,14 +59,14 @@ var RolePermissions = map[string][]string{
 	},
 	RoleManager: {
 		PermissionAssetRead, PermissionAssetWrite, PermissionAssetDelete,
-		PermissionWORead, PermissionWOWrite, PermissionWOAssign, PermissionWOClose,
+		PermissionWORead, PermissionWOWrite, PermissionWOAssign, PermissionWOApprove, PermissionWOClose,
 		PermissionInventoryRead, PermissionInventoryWrite,
 		PermissionReportView,
 		PermissionUserManage,
 	},
 	RoleAdmin: {
 		PermissionAssetRead, PermissionAssetWrite, PermissionAssetDelete,
-		PermissionWORead, PermissionWOWrite, PermissionWOAssign, PermissionWOClose,
+		PermissionWORead, PermissionWOWrite, PermissionWOAssign, PermissionWOApprove, PermissionWOClose,
 		PermissionInventoryRead, PermissionInventoryWrite,
 		PermissionReportView,
 		PermissionUserManage,
@@ -75,6 +76,14 @@ var RolePermissions = map[string][]string{
 	},
 }
 
+// AgileRolePermissions are granted on top of RolePermissions in tenants with
+// Agile Mode enabled, letting small teams skip the Supervisor approval step.
+// They only apply to a user's own work orders, so they are checked by the work order
+// service alongside ownership and never by RequirePermission.
+var AgileRolePermissions = map[string][]string{
+	RoleTechnician: {PermissionWOApprove, PermissionWOClose},
+}
+
 // AuthMiddleware validates the JWT token and adds claims to context
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -127,7 +136,10 @@ func RequireRole(roles ...string) func(http.Handler) http.Handler {
 	}
 }
 
-// RequirePermission returns middleware that checks if user's role has the required permission
+// RequirePermission returns middleware that checks if user's role has the required permission.
+// Agile Mode permissions are not considered: they only cover the user's own work
+// orders, so WorkOrderService grants them. Routes that approve or close work orders
+// must go through that service instead of gating on wo:approve or wo:close here.
 func RequirePermission(permission string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -175,6 +187,16 @@ func HasPermission(role, permission string) bool {
 	return false
 }
 
+// HasAgilePermission checks if a role gains a permission when Agile Mode is enabled
+func HasAgilePermission(role, permission string) bool {
+	for _, p := range AgileRolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
 func errorResponse(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
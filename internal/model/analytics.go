@@ -1,13 +1,59 @@
 package model
 
+import (
+	"time"
+)
+
 // DashboardStats aggregates high-level KPIs for the dashboard
 type DashboardStats struct {
 	TotalAssets    int `json:"totalAssets"`
-	MaintenanceDue int `json:"maintenanceDue"`
-	OpenFaults     int `json:"openFaults"`
-	CriticalAlerts int `json:"criticalAlerts"`
+	MaintenanceDue int `json:"maintenanceDue"` // Open High/Critical work orders
+	OpenFaults     int `json:"openFaults"`     // Open corrective work orders
+	CriticalAlerts int `json:"criticalAlerts"` // Same as CriticalDown, kept for existing clients
 	OpenWorkOrders int `json:"openWorkOrders"`
 	CompletionRate int `json:"completionRate"`
+
+	// Real-time tiles, each with a drill-down list under /analytics/tiles
+	CriticalDown     int     `json:"criticalDown"`     // Assets Down or Red_Tag
+	OverduePMs       int     `json:"overduePMs"`       // Active PM schedules past a trigger
+	WalletWatch      int     `json:"walletWatch"`      // Users holding more than the tenant's wallet threshold
+	WalletWatchValue float64 `json:"walletWatchValue"` // Total value held by those users
+	DataHealthPct    float64 `json:"dataHealthPct"`    // In-service assets with a meter reading in the last 7 days
+}
+
+// DataHealthWindowDays is how recent a meter reading must be to count as healthy
+const DataHealthWindowDays = 7
+
+// DefaultWalletWatchThreshold is the wallet value above which a user appears on
+// Wallet Watch when the tenant has not set walletWatchThreshold
+const DefaultWalletWatchThreshold = 500.0
+
+// CriticalDownAsset is an asset currently Down or Red_Tag
+type CriticalDownAsset struct {
+	AssetID     string     `json:"assetId"`
+	AssetName   string     `json:"assetName"`
+	Status      string     `json:"status"`
+	LocationID  *string    `json:"locationId,omitempty"`
+	DownSince   *time.Time `json:"downSince,omitempty"`
+	DownHours   float64    `json:"downHours"`
+	WorkOrderID *string    `json:"workOrderId,omitempty"` // Work order that took the asset down
+}
+
+// WalletWatchEntry is a user's wallet holding above the tenant threshold
+type WalletWatchEntry struct {
+	UserID      string            `json:"userId"`
+	UserName    string            `json:"userName"`
+	TotalValue  float64           `json:"totalValue"`
+	UnpricedQty float64           `json:"unpricedQty"` // Held parts with no unit cost
+	Holdings    []InventoryWallet `json:"holdings"`
+}
+
+// DataHealthAsset is an in-service asset whose meters are stale or missing
+type DataHealthAsset struct {
+	AssetID       string     `json:"assetId"`
+	AssetName     string     `json:"assetName"`
+	Status        string     `json:"status"`
+	LastReadingAt *time.Time `json:"lastReadingAt,omitempty"` // Nil when never read
 }
 
 // Alert represents a high-priority system notification
@@ -25,3 +71,130 @@ type AnalyticsResponse struct {
 	Stats  DashboardStats `json:"stats"`
 	Alerts []Alert        `json:"alerts"`
 }
+
+// Reliability grouping keys
+const (
+	ReliabilityByAsset    = "asset"
+	ReliabilityByModel    = "model" // manufacturer + model_number
+	ReliabilityByLocation = "location"
+	ReliabilityByOrgUnit  = "orgUnit"
+)
+
+// ReliabilityParams selects the assets and period for reliability metrics
+type ReliabilityParams struct {
+	TenantID   string
+	GroupBy    string
+	LocationID string // Location subtree
+	OrgUnitID  string // Org unit subtree
+	AssetID    string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// ReliabilityMetrics are MTTR, MTBF and availability for one group of assets.
+// Failures are corrective (non-PM), non-cancelled work orders raised in the period;
+// repairs are those completed in the period.
+type ReliabilityMetrics struct {
+	Key             string   `json:"key"`
+	Label           string   `json:"label"`
+	Assets          int      `json:"assets"`
+	Failures        int      `json:"failures"`
+	Repairs         int      `json:"repairs"`
+	ExposureHours   float64  `json:"exposureHours"` // Asset-hours in the period
+	DowntimeHours   float64  `json:"downtimeHours"`
+	RepairHours     float64  `json:"repairHours"` // Reported to work complete, summed
+	MTTRHours       *float64 `json:"mttrHours"`   // Nil without repairs
+	MTBFHours       *float64 `json:"mtbfHours"`   // Nil without failures
+	AvailabilityPct float64  `json:"availabilityPct"`
+}
+
+// Compute derives MTTR, MTBF and availability from the summed components
+func (m *ReliabilityMetrics) Compute() {
+	m.MTTRHours, m.MTBFHours = nil, nil
+	if m.Repairs > 0 {
+		mttr := m.RepairHours / float64(m.Repairs)
+		m.MTTRHours = &mttr
+	}
+	uptime := m.ExposureHours - m.DowntimeHours
+	if uptime < 0 {
+		uptime = 0
+	}
+	if m.Failures > 0 {
+		mtbf := uptime / float64(m.Failures)
+		m.MTBFHours = &mtbf
+	}
+	m.AvailabilityPct = Availability(m.ExposureHours, m.DowntimeHours)
+}
+
+// Add accumulates another group's components into m
+func (m *ReliabilityMetrics) Add(o ReliabilityMetrics) {
+	m.Assets += o.Assets
+	m.Failures += o.Failures
+	m.Repairs += o.Repairs
+	m.ExposureHours += o.ExposureHours
+	m.DowntimeHours += o.DowntimeHours
+	m.RepairHours += o.RepairHours
+}
+
+// Bad actor ranking metrics
+const (
+	BadActorByTotalCost  = "totalCost" // Labor cost + parts cost
+	BadActorByLaborHours = "laborHours"
+	BadActorByLaborCost  = "laborCost"
+	BadActorByPartsCost  = "partsCost"
+	BadActorByWorkOrders = "workOrders"
+	BadActorByDowntime   = "downtimeHours"
+)
+
+// BadActorParams selects the grouping, ranking and period for the bad actor report
+type BadActorParams struct {
+	TenantID string
+	GroupBy  string // Reliability grouping keys
+	SortBy   string
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
+
+// BadActor is one asset (or asset model, location, org unit) ranked by the budget
+// and time it consumed in a period
+type BadActor struct {
+	Rank          int     `json:"rank"`
+	Key           string  `json:"key"`
+	Label         string  `json:"label"`
+	Assets        int     `json:"assets"` // Assets with activity in the period
+	WorkOrders    int     `json:"workOrders"`
+	LaborHours    float64 `json:"laborHours"`
+	LaborCost     float64 `json:"laborCost"`
+	PartsCost     float64 `json:"partsCost"`
+	TotalCost     float64 `json:"totalCost"`
+	DowntimeHours float64 `json:"downtimeHours"`
+}
+
+// Trend bucket intervals
+const (
+	TrendIntervalDay   = "day"
+	TrendIntervalWeek  = "week" // Starting Monday
+	TrendIntervalMonth = "month"
+)
+
+// PMComplianceGraceDays is how long a preventive work order without an SLA
+// resolution deadline has to be completed to count as on time
+const PMComplianceGraceDays = 7
+
+// TrendBucket aggregates the daily snapshots in one day, week or month
+type TrendBucket struct {
+	Start           string   `json:"start"` // First day of the bucket, YYYY-MM-DD
+	Days            int      `json:"days"`  // Daily snapshots in the bucket
+	Opened          int      `json:"opened"`
+	Closed          int      `json:"closed"`
+	Backlog         *int     `json:"backlog"` // Open at the end of the bucket's last snapshot
+	PMDue           int      `json:"pmDue"`
+	PMOnTime        int      `json:"pmOnTime"`
+	PMCompliancePct *float64 `json:"pmCompliancePct"` // Nil when no PM work was due
+	LaborCost       float64  `json:"laborCost"`
+	PartsCost       float64  `json:"partsCost"`
+	Spend           float64  `json:"spend"` // Labor + parts
+	DowntimeHours   float64  `json:"downtimeHours"`
+}
@@ -23,8 +23,10 @@ type Asset struct {
 	UpdatedAt       time.Time       `json:"updatedAt"`
 
 	// Computed/Joined fields
-	LocationName string `json:"location,omitempty"`
-	OrgUnitName  string `json:"orgUnit,omitempty"`
+	LocationName string  `json:"location,omitempty"`
+	OrgUnitName  string  `json:"orgUnit,omitempty"`
+	Depth        int     `json:"depth,omitempty"`    // Levels below (descendants) or above (ancestors) the asset asked about
+	Children     []Asset `json:"children,omitempty"` // Tree view
 }
 
 // AssetStatus constants (v1.1)
@@ -36,6 +38,71 @@ const (
 	AssetStatusRedTag   = "Red_Tag"
 )
 
+// AssetTransitions lists the statuses reachable from each asset status. Any
+// status may be archived (decommissioned); an archived asset comes back as Draft
+// so it is verified again before returning to service.
+var AssetTransitions = map[string][]string{
+	AssetStatusDraft:    {AssetStatusActive, AssetStatusArchived},
+	AssetStatusActive:   {AssetStatusDown, AssetStatusRedTag, AssetStatusArchived},
+	AssetStatusDown:     {AssetStatusActive, AssetStatusRedTag, AssetStatusArchived},
+	AssetStatusRedTag:   {AssetStatusActive, AssetStatusDown, AssetStatusArchived},
+	AssetStatusArchived: {AssetStatusDraft},
+}
+
+// IsValidAssetStatus reports whether status is a known asset status
+func IsValidAssetStatus(status string) bool {
+	_, ok := AssetTransitions[status]
+	return ok
+}
+
+// CanTransitionAsset reports whether an asset may move from one status to another
+func CanTransitionAsset(from, to string) bool {
+	for _, next := range AssetTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDownStatus reports whether an asset in status is out of service
+func IsDownStatus(status string) bool {
+	return status == AssetStatusDown || status == AssetStatusRedTag
+}
+
+// NestAssets arranges a flat list of assets into trees. Assets whose parent is not
+// in the list are roots; order within each level is kept.
+func NestAssets(assets []Asset) []Asset {
+	children := map[string][]int{}
+	present := make(map[string]bool, len(assets))
+	for _, a := range assets {
+		present[a.ID] = true
+	}
+	var roots []int
+	for i, a := range assets {
+		if a.ParentID != nil && present[*a.ParentID] {
+			children[*a.ParentID] = append(children[*a.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var build func(i int) Asset
+	build = func(i int) Asset {
+		node := assets[i]
+		for _, c := range children[node.ID] {
+			node.Children = append(node.Children, build(c))
+		}
+		return node
+	}
+
+	tree := make([]Asset, 0, len(roots))
+	for _, i := range roots {
+		tree = append(tree, build(i))
+	}
+	return tree
+}
+
 // AssetListParams for filtering and pagination
 type AssetListParams struct {
 	TenantID  string
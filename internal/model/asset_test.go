@@ -0,0 +1,82 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanTransitionAsset(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{AssetStatusDraft, AssetStatusActive, true},
+		{AssetStatusDraft, AssetStatusArchived, true},
+		{AssetStatusActive, AssetStatusDown, true},
+		{AssetStatusActive, AssetStatusRedTag, true},
+		{AssetStatusActive, AssetStatusArchived, true},
+		{AssetStatusDown, AssetStatusActive, true},
+		{AssetStatusDown, AssetStatusRedTag, true},
+		{AssetStatusRedTag, AssetStatusActive, true},
+		{AssetStatusRedTag, AssetStatusDown, true},
+		{AssetStatusRedTag, AssetStatusArchived, true},
+		{AssetStatusArchived, AssetStatusDraft, true}, // re-verified before service
+
+		{AssetStatusDraft, AssetStatusDown, false}, // never verified
+		{AssetStatusDraft, AssetStatusRedTag, false},
+		{AssetStatusArchived, AssetStatusActive, false},
+		{AssetStatusArchived, AssetStatusDown, false},
+		{AssetStatusActive, AssetStatusDraft, false},
+		{AssetStatusActive, AssetStatusActive, false},
+		{"Retired", AssetStatusActive, false},
+		{AssetStatusActive, "Retired", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionAsset(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionAsset(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestNestAssets(t *testing.T) {
+	asset := func(id, parent string) Asset {
+		a := Asset{ID: id}
+		if parent != "" {
+			a.ParentID = &parent
+		}
+		return a
+	}
+
+	tests := []struct {
+		name   string
+		assets []Asset
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"flat", []Asset{asset("a", ""), asset("b", "")}, "a b"},
+		{"nested", []Asset{asset("a", ""), asset("b", "a"), asset("c", "b"), asset("d", "a")}, "a(b(c) d)"},
+		{"children before parent", []Asset{asset("c", "b"), asset("b", "a"), asset("a", "")}, "a(b(c))"},
+		{"sibling order kept", []Asset{asset("p", ""), asset("z", "p"), asset("m", "p"), asset("a", "p")}, "p(z m a)"},
+		{"parent outside list", []Asset{asset("b", "a"), asset("c", "b"), asset("x", "")}, "b(c) x"},
+	}
+
+	for _, tt := range tests {
+		if got := formatTree(NestAssets(tt.assets)); got != tt.want {
+			t.Errorf("%s: NestAssets = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// formatTree renders a tree as "id(child child)" for comparison
+func formatTree(assets []Asset) string {
+	parts := make([]string, 0, len(assets))
+	for _, a := range assets {
+		if len(a.Children) > 0 {
+			parts = append(parts, a.ID+"("+formatTree(a.Children)+")")
+		} else {
+			parts = append(parts, a.ID)
+		}
+	}
+	return strings.Join(parts, " ")
+}
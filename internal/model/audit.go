@@ -7,7 +7,8 @@ import (
 // AuditLog represents an audit trail entry
 type AuditLog struct {
 	ID         string                 `json:"id"`
-	UserID     string                 `json:"userId"`
+	TenantID   string                 `json:"-"`
+	UserID     string                 `json:"userId"`             // Empty for system-generated events
 	UserName   string                 `json:"userName,omitempty"` // Joined field
 	Action     string                 `json:"action"`
 	EntityType string                 `json:"entityType"`
@@ -38,6 +39,9 @@ const (
 	AuditActionStatusChange = "status_change"
 	AuditActionLogin        = "login"
 	AuditActionAssign       = "assign"
+	AuditActionConsume      = "consume"
+	AuditActionEscalate     = "escalate"
+	AuditActionMove         = "move"
 )
 
 // Common Entity Types
@@ -47,4 +51,5 @@ const (
 	AuditEntityUser      = "user"
 	AuditEntityLocation  = "location"
 	AuditEntityTenant    = "tenant"
+	AuditEntityMovement  = "asset_movement"
 )
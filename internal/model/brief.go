@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+)
+
+// WeeklyBrief is the Monday Morning Brief for one tenant and week
+type WeeklyBrief struct {
+	TenantID   string    `json:"tenantId"`
+	TenantName string    `json:"tenantName"`
+	WeekStart  time.Time `json:"weekStart"` // Monday 00:00 UTC
+	WeekEnd    time.Time `json:"weekEnd"`   // Following Monday, exclusive
+
+	// Work order activity during the week
+	Opened              int             `json:"opened"`
+	Completed           int             `json:"completed"`
+	CompletedWorkOrders []WorkOrderLink `json:"completedWorkOrders"` // Latest first, capped
+
+	// State when the brief was generated
+	OverduePMs     []PMSchedule        `json:"overduePMs"`
+	CriticalAssets []CriticalDownAsset `json:"criticalAssets"`
+	LowStock       []InventoryStock    `json:"lowStock"`
+	LowStockTotal  int                 `json:"lowStockTotal"`
+
+	// KPIs
+	Stats       DashboardStats     `json:"stats"`
+	Reliability ReliabilityMetrics `json:"reliability"` // All assets over the week
+
+	GeneratedAt time.Time `json:"generatedAt"`
+}
+
+// BriefSubscriber is a user who receives the weekly brief by email
+type BriefSubscriber struct {
+	UserID   string `json:"userId"`
+	TenantID string `json:"tenantId"`
+	Email    string `json:"email"`
+	FullName string `json:"fullName"`
+	Role     string `json:"role"`
+}
+
+// BriefWeekStart returns the Monday 00:00 UTC that starts the week containing t
+func BriefWeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7 // Days since Monday
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// ChecklistTemplate is a reusable list of steps expanded into wo_tasks (v1.1 schema)
+type ChecklistTemplate struct {
+	ID          string          `json:"id"`
+	TenantID    string          `json:"tenantId"`
+	Title       string          `json:"title"`
+	Description *string         `json:"description,omitempty"`
+	Items       []ChecklistItem `json:"items"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// ChecklistItem is one step of a checklist template, stored in checklist_templates.items.
+// Items are expanded into tasks in array order.
+type ChecklistItem struct {
+	Label       string   `json:"label"`
+	Type        string   `json:"type"`
+	IsMandatory bool     `json:"isMandatory"`
+	MinValue    *float64 `json:"minValue,omitempty"` // numeric items only
+	MaxValue    *float64 `json:"maxValue,omitempty"` // numeric items only
+}
+
+// WOTask is an execution step of a work order (v1.1 schema)
+type WOTask struct {
+	ID                string     `json:"id"`
+	WorkOrderID       string     `json:"workOrderId"`
+	Description       string     `json:"description"`
+	TaskType          string     `json:"taskType"`
+	IsMandatory       bool       `json:"isMandatory"`
+	SortOrder         int        `json:"sortOrder"`
+	MinValue          *float64   `json:"minValue,omitempty"`
+	MaxValue          *float64   `json:"maxValue,omitempty"`
+	ResultValue       *string    `json:"resultValue,omitempty"`
+	ResultNotes       *string    `json:"resultNotes,omitempty"`
+	PhotoURL          *string    `json:"photoUrl,omitempty"`
+	CompletedAt       *time.Time `json:"completedAt,omitempty"`
+	CompletedByUserID *string    `json:"completedByUserId,omitempty"`
+
+	// Computed/Joined fields
+	Failed              bool    `json:"failed"`
+	FollowUpWorkOrderID *string `json:"followUpWorkOrderId,omitempty"`
+}
+
+// Task type constants
+const (
+	TaskTypePassFail = "pass_fail"
+	TaskTypeNumeric  = "numeric"
+	TaskTypeText     = "text"
+	TaskTypePhoto    = "photo"
+)
+
+// IsValidTaskType reports whether t is a known task type
+func IsValidTaskType(t string) bool {
+	switch t {
+	case TaskTypePassFail, TaskTypeNumeric, TaskTypeText, TaskTypePhoto:
+		return true
+	}
+	return false
+}
+
+// Pass/fail task results
+const (
+	TaskResultPass = "Pass"
+	TaskResultFail = "Fail"
+)
+
+// IsOpen reports whether the task has not been completed yet
+func (t *WOTask) IsOpen() bool {
+	return t.CompletedAt == nil
+}
+
+// IsFailed reports whether the recorded result is a failure: a pass/fail task
+// marked Fail, or a numeric reading outside the task's limits.
+func (t *WOTask) IsFailed() bool {
+	if t.ResultValue == nil {
+		return false
+	}
+
+	switch t.TaskType {
+	case TaskTypePassFail:
+		return *t.ResultValue == TaskResultFail
+	case TaskTypeNumeric:
+		v, err := strconv.ParseFloat(*t.ResultValue, 64)
+		if err != nil {
+			return false
+		}
+		return !t.WithinLimits(v)
+	}
+	return false
+}
+
+// WithinLimits reports whether a numeric reading is inside the task's min/max range
+func (t *WOTask) WithinLimits(v float64) bool {
+	if t.MinValue != nil && v < *t.MinValue {
+		return false
+	}
+	if t.MaxValue != nil && v > *t.MaxValue {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package model
+
+import "testing"
+
+func TestWOTaskWithinLimits(t *testing.T) {
+	lo, hi := 10.0, 20.0
+
+	tests := []struct {
+		name     string
+		min, max *float64
+		v        float64
+		want     bool
+	}{
+		{"no limits", nil, nil, -1e9, true},
+		{"inside", &lo, &hi, 15, true},
+		{"at min", &lo, &hi, 10, true},
+		{"at max", &lo, &hi, 20, true},
+		{"below min", &lo, &hi, 9.99, false},
+		{"above max", &lo, &hi, 20.01, false},
+		{"min only", &lo, nil, 1e9, true},
+		{"min only below", &lo, nil, 5, false},
+		{"max only", nil, &hi, -1e9, true},
+		{"max only above", nil, &hi, 25, false},
+	}
+
+	for _, tt := range tests {
+		task := &WOTask{TaskType: TaskTypeNumeric, MinValue: tt.min, MaxValue: tt.max}
+		if got := task.WithinLimits(tt.v); got != tt.want {
+			t.Errorf("%s: WithinLimits(%v) = %v, want %v", tt.name, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestWOTaskIsFailed(t *testing.T) {
+	lo, hi := 10.0, 20.0
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name   string
+		task   WOTask
+		failed bool
+	}{
+		{"no result", WOTask{TaskType: TaskTypePassFail}, false},
+		{"pass", WOTask{TaskType: TaskTypePassFail, ResultValue: str(TaskResultPass)}, false},
+		{"fail", WOTask{TaskType: TaskTypePassFail, ResultValue: str(TaskResultFail)}, true},
+		{"numeric inside", WOTask{TaskType: TaskTypeNumeric, MinValue: &lo, MaxValue: &hi, ResultValue: str("12.5")}, false},
+		{"numeric below", WOTask{TaskType: TaskTypeNumeric, MinValue: &lo, MaxValue: &hi, ResultValue: str("9")}, true},
+		{"numeric above", WOTask{TaskType: TaskTypeNumeric, MinValue: &lo, MaxValue: &hi, ResultValue: str("21")}, true},
+		{"numeric unparsable", WOTask{TaskType: TaskTypeNumeric, MinValue: &lo, ResultValue: str("n/a")}, false},
+		{"numeric no limits", WOTask{TaskType: TaskTypeNumeric, ResultValue: str("-40")}, false},
+		{"text", WOTask{TaskType: TaskTypeText, ResultValue: str(TaskResultFail)}, false},
+		{"photo", WOTask{TaskType: TaskTypePhoto, ResultValue: str("photo.jpg")}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.task.IsFailed(); got != tt.failed {
+			t.Errorf("%s: IsFailed() = %v, want %v", tt.name, got, tt.failed)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+)
+
+// WOComment is a comment on a work order. Replies hang off a top-level comment.
+type WOComment struct {
+	ID          string     `json:"id"`
+	WorkOrderID string     `json:"workOrderId"`
+	ParentID    *string    `json:"parentId,omitempty"`
+	UserID      string     `json:"userId"`
+	Body        string     `json:"body"`
+	Mentions    []string   `json:"mentions"` // Mentioned user IDs
+	CreatedAt   time.Time  `json:"createdAt"`
+	EditedAt    *time.Time `json:"editedAt,omitempty"`
+
+	// Computed/Joined fields
+	UserName       string      `json:"userName,omitempty"`
+	WorkOrderTitle string      `json:"workOrderTitle,omitempty"`
+	Replies        []WOComment `json:"replies,omitempty"`
+}
+
+// TimelineEntry is one event in a work order's activity timeline
+type TimelineEntry struct {
+	Kind     string                 `json:"kind"`
+	At       time.Time              `json:"at"`
+	UserID   *string                `json:"userId,omitempty"`
+	UserName string                 `json:"userName,omitempty"`
+	RefID    string                 `json:"refId"` // ID of the comment, task, log or audit entry
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// Timeline entry kinds. Audit entries without a dedicated kind use their audit action.
+const (
+	TimelineKindCreated      = "created"
+	TimelineKindComment      = "comment"
+	TimelineKindStatusChange = "status_change"
+	TimelineKindAssign       = "assign"
+	TimelineKindTask         = "task"
+	TimelineKindLabor        = "labor"
+	TimelineKindPart         = "part"
+	TimelineKindFollowUp     = "follow_up"
+)
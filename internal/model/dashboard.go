@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+)
+
+// Dashboard widget IDs
+const (
+	WidgetKPISummary   = "kpi_summary"
+	WidgetCriticalDown = "critical_down"
+	WidgetOverduePMs   = "overdue_pms"
+	WidgetDataHealth   = "data_health"
+	WidgetMyJobs       = "my_jobs"
+	WidgetMyWallet     = "my_wallet"
+	WidgetTeamWorkload = "team_workload"
+	WidgetSLAOverdue   = "sla_overdue"
+	WidgetLowStock     = "low_stock"
+	WidgetWalletWatch  = "wallet_watch"
+	WidgetSpendTrend   = "spend_trend"
+	WidgetBadActors    = "bad_actors"
+	WidgetReliability  = "reliability"
+)
+
+// DashboardGridColumns is the width of the dashboard grid
+const DashboardGridColumns = 12
+
+// Dashboard layout sources, from most to least specific
+const (
+	DashboardSourceUser    = "user"    // The user's own layout
+	DashboardSourceRole    = "role"    // The tenant's default for the user's role
+	DashboardSourceDefault = "default" // Built-in default for the role
+)
+
+// DashboardWidget describes a widget available for dashboards
+type DashboardWidget struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Permission    string `json:"permission,omitempty"` // Needed to see the widget; empty for everyone
+	DefaultWidth  int    `json:"defaultWidth"`
+	DefaultHeight int    `json:"defaultHeight"`
+}
+
+// DashboardPlacement positions a widget on the dashboard grid
+type DashboardPlacement struct {
+	Widget string `json:"widget"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	W      int    `json:"w"`
+	H      int    `json:"h"`
+}
+
+// DashboardLayout is a stored or built-in arrangement of widgets
+type DashboardLayout struct {
+	Source    string               `json:"source"`
+	Role      string               `json:"role"`
+	Widgets   []DashboardPlacement `json:"widgets"`
+	UpdatedAt *time.Time           `json:"updatedAt,omitempty"`
+}
+
+// DashboardWidgetData is one widget's data on a loaded dashboard; Error is set
+// instead when the widget failed to load
+type DashboardWidgetData struct {
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
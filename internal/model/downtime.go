@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+)
+
+// DowntimeEvent is a spell during which an asset was Down or Red_Tag
+type DowntimeEvent struct {
+	ID          string     `json:"id"`
+	AssetID     string     `json:"assetId"`
+	Status      string     `json:"status"` // Latest down status of the spell
+	WorkOrderID *string    `json:"workOrderId,omitempty"`
+	StartedAt   time.Time  `json:"startedAt"`
+	EndedAt     *time.Time `json:"endedAt,omitempty"` // Nil while still down
+
+	// Computed fields
+	Hours float64 `json:"hours"` // Duration inside the requested period
+}
+
+// AssetAvailability summarizes an asset's downtime over a period
+type AssetAvailability struct {
+	AssetID         string  `json:"assetId"`
+	AssetName       string  `json:"assetName"`
+	Status          string  `json:"status"`
+	PeriodHours     float64 `json:"periodHours"`
+	DowntimeHours   float64 `json:"downtimeHours"`
+	Events          int     `json:"events"`
+	AvailabilityPct float64 `json:"availabilityPct"`
+}
+
+// Period is a half-open time range [From, To)
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// Hours returns the length of the period, counting only time that has passed
+func (p Period) Hours() float64 {
+	to := p.To
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+	if !to.After(p.From) {
+		return 0
+	}
+	return to.Sub(p.From).Hours()
+}
+
+// Availability returns the percentage of periodHours not spent down
+func Availability(periodHours, downtimeHours float64) float64 {
+	if periodHours <= 0 {
+		return 100
+	}
+	pct := (periodHours - downtimeHours) / periodHours * 100
+	if pct < 0 {
+		return 0
+	}
+	return pct
+}
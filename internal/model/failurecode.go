@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+)
+
+// FailureCode is an entry of a tenant's failure code catalog. Codes form a
+// three-level hierarchy: a Problem has Causes, and a Cause has Remedies.
+type FailureCode struct {
+	ID          string    `json:"id"`
+	TenantID    string    `json:"tenantId"`
+	ParentID    *string   `json:"parentId,omitempty"`
+	Level       string    `json:"level"`
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	IsActive    bool      `json:"isActive"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	// Computed fields
+	Children []FailureCode `json:"children,omitempty"`
+}
+
+// FailureCode level constants
+const (
+	FailureLevelProblem = "Problem"
+	FailureLevelCause   = "Cause"
+	FailureLevelRemedy  = "Remedy"
+)
+
+// FailureParentLevel maps each level to the level its parent must have ("" = root)
+var FailureParentLevel = map[string]string{
+	FailureLevelProblem: "",
+	FailureLevelCause:   FailureLevelProblem,
+	FailureLevelRemedy:  FailureLevelCause,
+}
+
+// WOCloseout is the close-out data recorded when a work order is Closed
+type WOCloseout struct {
+	ProblemCodeID *string `json:"problemCodeId"`
+	CauseCodeID   *string `json:"causeCodeId"`
+	RemedyCodeID  *string `json:"remedyCodeId"`
+	Notes         *string `json:"notes"`
+}
+
+// RequiresFailureCodes reports whether closing a work order of this origin needs
+// failure codes: corrective requests and defect follow-ups describe a failure.
+func RequiresFailureCodes(origin string) bool {
+	return origin == WOOriginManualRequest || origin == WOOriginDefectFollowup
+}
@@ -14,6 +14,7 @@ type Part struct {
 	UOM           string    `json:"uom"`
 	MinStockLevel float64   `json:"minStockLevel"`
 	IsStockItem   bool      `json:"isStockItem"`
+	UnitCost      *float64  `json:"unitCost,omitempty"`
 	CreatedAt     time.Time `json:"createdAt"`
 }
 
@@ -37,9 +38,10 @@ type InventoryStock struct {
 	UpdatedAt      time.Time `json:"updatedAt"`
 
 	// Joined fields
-	PartName     string `json:"partName,omitempty"`
-	PartSKU      string `json:"partSku,omitempty"`
-	LocationName string `json:"locationName,omitempty"`
+	PartName      string  `json:"partName,omitempty"`
+	PartSKU       string  `json:"partSku,omitempty"`
+	MinStockLevel float64 `json:"minStockLevel"`
+	LocationName  string  `json:"locationName,omitempty"`
 }
 
 // InventoryStockListParams for filtering inventory
@@ -60,9 +62,10 @@ type InventoryWallet struct {
 	LastUpdatedAt time.Time `json:"lastUpdatedAt"`
 
 	// Joined fields
-	PartName string `json:"partName,omitempty"`
-	PartSKU  string `json:"partSku,omitempty"`
-	UserName string `json:"userName,omitempty"`
+	PartName string   `json:"partName,omitempty"`
+	PartSKU  string   `json:"partSku,omitempty"`
+	UserName string   `json:"userName,omitempty"`
+	UnitCost *float64 `json:"unitCost,omitempty"`
 }
 
 // StockTransfer represents a transfer between locations or to/from wallets
@@ -75,3 +78,27 @@ type StockTransfer struct {
 	Quantity       float64 `json:"quantity"`
 	Notes          *string `json:"notes,omitempty"`
 }
+
+// ResourceLog is a part consumed on a work order (v1.1 schema)
+type ResourceLog struct {
+	ID          string    `json:"id"`
+	WorkOrderID string    `json:"workOrderId"`
+	PartID      *string   `json:"partId,omitempty"`
+	PartName    *string   `json:"partName,omitempty"`
+	Quantity    float64   `json:"quantity"`
+	Source      string    `json:"source"`
+	UserID      *string   `json:"userId,omitempty"`
+	LocationID  *string   `json:"locationId,omitempty"` // Warehouse source only
+	UnitCost    *float64  `json:"unitCost,omitempty"`   // Part's unit cost when consumed
+	CreatedAt   time.Time `json:"createdAt"`
+
+	// Joined fields
+	PartSKU  string `json:"partSku,omitempty"`
+	UserName string `json:"userName,omitempty"`
+}
+
+// Resource source constants: where a consumed part was drawn from
+const (
+	ResourceSourceWallet    = "Wallet"
+	ResourceSourceWarehouse = "Warehouse"
+)
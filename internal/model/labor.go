@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+// LaborLog is time a user spent on a work order (v1.1 schema)
+type LaborLog struct {
+	ID            string    `json:"id"`
+	WorkOrderID   string    `json:"workOrderId"`
+	UserID        string    `json:"userId"`
+	HoursSpent    float64   `json:"hoursSpent"`
+	DatePerformed time.Time `json:"datePerformed"`
+	Comment       *string   `json:"comment,omitempty"`
+
+	// Computed/Joined fields
+	UserName   string  `json:"userName,omitempty"`
+	HourlyRate float64 `json:"hourlyRate"`
+	Cost       float64 `json:"cost"`
+}
+
+// LaborSummary totals the labor logged on a work order
+type LaborSummary struct {
+	TotalHours float64 `json:"totalHours"`
+	TotalCost  float64 `json:"totalCost"`
+	Entries    int     `json:"entries"`
+}
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// AssetMovement status constants (chain of custody)
+const (
+	MovementStatusPendingApproval = "Pending_Approval" // Requested by a technician, awaiting a supervisor
+	MovementStatusInTransit       = "In_Transit"
+	MovementStatusReceived        = "Received"
+	MovementStatusDisputed        = "Disputed"
+	MovementStatusRejected        = "Rejected"
+)
+
+// MovementTransitions lists the statuses reachable from each movement status. A
+// dispute (damaged, wrong or missing item) can be raised in transit or after
+// receipt, and is resolved by receiving the item.
+var MovementTransitions = map[string][]string{
+	MovementStatusPendingApproval: {MovementStatusInTransit, MovementStatusRejected},
+	MovementStatusInTransit:       {MovementStatusReceived, MovementStatusDisputed},
+	MovementStatusReceived:        {MovementStatusDisputed},
+	MovementStatusDisputed:        {MovementStatusReceived},
+	MovementStatusRejected:        {},
+}
+
+// OpenMovementStatuses are the statuses of a transfer still under way; an asset
+// with an open movement cannot be moved again
+var OpenMovementStatuses = []string{MovementStatusPendingApproval, MovementStatusInTransit}
+
+// CanTransitionMovement reports whether a movement may move from one status to another
+func CanTransitionMovement(from, to string) bool {
+	for _, next := range MovementTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AssetMovement transfer types
+const (
+	TransferTypeRelocation = "Relocation" // Immediate move, received on creation
+	TransferTypeTransfer   = "Transfer"   // Shipped between locations
+	TransferTypeRepair     = "Repair"     // Sent out for repair
+	TransferTypeLoan       = "Loan"
+)
+
+// IsValidTransferType reports whether t may be requested for a transfer
+func IsValidTransferType(t string) bool {
+	switch t {
+	case TransferTypeTransfer, TransferTypeRepair, TransferTypeLoan:
+		return true
+	}
+	return false
+}
+
+// AssetMovement records one asset leaving one location for another
+type AssetMovement struct {
+	ID               string     `json:"id"`
+	TenantID         string     `json:"tenantId"`
+	AssetID          string     `json:"assetId"`
+	FromLocationID   *string    `json:"fromLocationId,omitempty"`
+	ToLocationID     *string    `json:"toLocationId,omitempty"`
+	Status           string     `json:"status"`
+	TransferType     string     `json:"transferType"`
+	ShipmentRef      string     `json:"shipmentRef"`
+	Notes            *string    `json:"notes,omitempty"`
+	CreatedByUserID  *string    `json:"createdByUserId,omitempty"`
+	ReviewedByUserID *string    `json:"reviewedByUserId,omitempty"`
+	ReviewedAt       *time.Time `json:"reviewedAt,omitempty"`
+	ReviewNote       *string    `json:"reviewNote,omitempty"`
+	ReceivedByUserID *string    `json:"receivedByUserId,omitempty"`
+	ReceivedAt       *time.Time `json:"receivedAt,omitempty"`
+	DisputedByUserID *string    `json:"disputedByUserId,omitempty"`
+	DisputedAt       *time.Time `json:"disputedAt,omitempty"`
+	DisputeReason    *string    `json:"disputeReason,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+
+	// Joined fields
+	AssetName        string  `json:"assetName,omitempty"`
+	AssetParentID    *string `json:"assetParentId,omitempty"`
+	FromLocationName string  `json:"fromLocation,omitempty"`
+	ToLocationName   string  `json:"toLocation,omitempty"`
+	CreatedByName    string  `json:"createdBy,omitempty"`
+}
+
+// AssetMovementListParams for filtering and pagination
+type AssetMovementListParams struct {
+	TenantID    string
+	Status      []string
+	ShipmentRef string
+	AssetID     string
+	Page        int
+	Limit       int
+}
+
+// TransferRequest asks for assets to be shipped to a location. Each asset travels
+// with everything below it.
+type TransferRequest struct {
+	TenantID     string
+	AssetIDs     []string
+	ToLocationID string
+	TransferType string
+	Notes        *string
+	Status       string // In_Transit, or Pending_Approval when approval is needed
+	UserID       string
+}
+
+// Shipment summarizes the movements sharing a shipment reference
+type Shipment struct {
+	ShipmentRef     string         `json:"shipmentRef"`
+	TransferType    string         `json:"transferType"`
+	ToLocationID    *string        `json:"toLocationId,omitempty"`
+	ToLocationName  string         `json:"toLocation,omitempty"`
+	CreatedByUserID *string        `json:"createdByUserId,omitempty"`
+	CreatedByName   string         `json:"createdBy,omitempty"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	Items           int            `json:"items"`
+	StatusCounts    map[string]int `json:"statusCounts"`
+}
+
+// NewShipmentRef generates the reference grouping the movements of one shipment,
+// e.g. "SHP-20240115-3FA9C1"
+func NewShipmentRef(now time.Time) string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return "SHP-" + now.UTC().Format("20060102") + "-" + strings.ToUpper(hex.EncodeToString(b))
+}
+
+// GatePass is the printable manifest of a shipment leaving the gate
+type GatePass struct {
+	TenantName  string
+	ShipmentRef string
+	Items       []GatePassItem
+	GeneratedAt time.Time
+	GeneratedBy string
+}
+
+// GatePassItem is one asset on a gate pass with the identities it can be checked by
+type GatePassItem struct {
+	AssetMovement
+	Identities []AssetIdentity
+}
+
+// Receipt exception reasons
+const (
+	ReceiptExceptionMissing       = "Missing"        // Reported missing; the movement is now Disputed
+	ReceiptExceptionNotInTransit  = "Not_In_Transit" // Left as it was, e.g. already received
+	ReceiptExceptionNotInShipment = "Not_In_Shipment"
+)
+
+// ReceiptException reports a shipment item that was not received
+type ReceiptException struct {
+	MovementID string `json:"movementId,omitempty"`
+	AssetID    string `json:"assetId"`
+	AssetName  string `json:"assetName,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+// ShipmentReceipt is the outcome of receiving a whole shipment
+type ShipmentReceipt struct {
+	ShipmentRef string             `json:"shipmentRef"`
+	Received    int                `json:"received"`
+	Exceptions  []ReceiptException `json:"exceptions"`
+	Items       []AssetMovement    `json:"data"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// Notification is an in-app message for a user
+type Notification struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"-"`
+	UserID      string     `json:"userId"`
+	Kind        string     `json:"kind"`
+	WorkOrderID *string    `json:"workOrderId,omitempty"`
+	Message     string     `json:"message"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ReadAt      *time.Time `json:"readAt,omitempty"`
+}
+
+// Notification kinds
+const (
+	NotificationKindSLABreach = "sla_breach"
+)
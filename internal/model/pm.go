@@ -0,0 +1,152 @@
+package model
+
+import (
+	"time"
+)
+
+// PMSchedule represents a preventive maintenance schedule (v1.1 schema).
+// A schedule is due when ANY of its configured intervals is reached ("race to zero").
+type PMSchedule struct {
+	ID                      string     `json:"id"`
+	AssetID                 string     `json:"assetId"`
+	ChecklistTemplateID     *string    `json:"checklistTemplateId,omitempty"`
+	Title                   string     `json:"title"`
+	IntervalDays            *int       `json:"intervalDays,omitempty"`
+	IntervalRunHours        *int       `json:"intervalRunHours,omitempty"`
+	IntervalOdometerKm      *int       `json:"intervalOdometerKm,omitempty"`
+	LastPerformedDate       *time.Time `json:"lastPerformedDate,omitempty"`
+	LastPerformedRunHours   *float64   `json:"lastPerformedRunHours,omitempty"`
+	LastPerformedOdometerKm *float64   `json:"lastPerformedOdometerKm,omitempty"`
+	SuppressPMIDs           []string   `json:"suppressPmIds"`
+	IsActive                bool       `json:"isActive"`
+
+	// Computed/Joined fields
+	TenantID          string  `json:"tenantId,omitempty"`
+	AssetName         string  `json:"assetName,omitempty"`
+	CurrentRunHours   float64 `json:"currentRunHours"`
+	CurrentOdometerKm float64 `json:"currentOdometerKm"`
+	OpenWorkOrderID   *string `json:"openWorkOrderId,omitempty"`
+}
+
+// PMTrigger constants identify which interval made a schedule due
+const (
+	PMTriggerCalendar = "calendar"
+	PMTriggerRunHours = "run_hours"
+	PMTriggerOdometer = "odometer"
+)
+
+// PMDueStatus is the result of evaluating a schedule against the calendar and meters
+type PMDueStatus struct {
+	ScheduleID        string     `json:"scheduleId"`
+	Due               bool       `json:"due"`
+	Triggers          []string   `json:"triggers"`
+	NextDueDate       *time.Time `json:"nextDueDate,omitempty"`
+	DaysRemaining     *int       `json:"daysRemaining,omitempty"`
+	RunHoursRemaining *float64   `json:"runHoursRemaining,omitempty"`
+	OdometerRemaining *float64   `json:"odometerKmRemaining,omitempty"`
+}
+
+// CheckDue evaluates the schedule's hybrid trigger at the given time using the
+// joined current meter readings. A missing baseline counts as due immediately.
+func (s *PMSchedule) CheckDue(now time.Time) PMDueStatus {
+	status := PMDueStatus{ScheduleID: s.ID, Triggers: []string{}}
+
+	if s.IntervalDays != nil && *s.IntervalDays > 0 {
+		if s.LastPerformedDate == nil {
+			status.Triggers = append(status.Triggers, PMTriggerCalendar)
+		} else {
+			next := s.LastPerformedDate.AddDate(0, 0, *s.IntervalDays)
+			days := int(next.Sub(truncateDay(now)).Hours() / 24)
+			status.NextDueDate = &next
+			status.DaysRemaining = &days
+			if days <= 0 {
+				status.Triggers = append(status.Triggers, PMTriggerCalendar)
+			}
+		}
+	}
+
+	if s.IntervalRunHours != nil && *s.IntervalRunHours > 0 {
+		remaining := remainingUsage(s.CurrentRunHours, s.LastPerformedRunHours, *s.IntervalRunHours)
+		status.RunHoursRemaining = &remaining
+		if remaining <= 0 {
+			status.Triggers = append(status.Triggers, PMTriggerRunHours)
+		}
+	}
+
+	if s.IntervalOdometerKm != nil && *s.IntervalOdometerKm > 0 {
+		remaining := remainingUsage(s.CurrentOdometerKm, s.LastPerformedOdometerKm, *s.IntervalOdometerKm)
+		status.OdometerRemaining = &remaining
+		if remaining <= 0 {
+			status.Triggers = append(status.Triggers, PMTriggerOdometer)
+		}
+	}
+
+	status.Due = len(status.Triggers) > 0
+	return status
+}
+
+// PMSuppressionWindowFraction sizes a schedule's suppression window as a fraction
+// of each of its intervals: an L2 service every 180 days covers an L1 service due
+// within the next 18 days, but not one performed yesterday
+const PMSuppressionWindowFraction = 0.1
+
+// PMWindow is a look-ahead on each kind of PM trigger
+type PMWindow struct {
+	Days       int
+	RunHours   float64
+	OdometerKm float64
+}
+
+// SuppressionWindow returns the look-ahead within which the schedule's service
+// covers the schedules it suppresses. A trigger the schedule does not use has no
+// look-ahead, so only schedules already due on it are covered.
+func (s *PMSchedule) SuppressionWindow() PMWindow {
+	var w PMWindow
+	if s.IntervalDays != nil && *s.IntervalDays > 0 {
+		w.Days = int(float64(*s.IntervalDays) * PMSuppressionWindowFraction)
+	}
+	if s.IntervalRunHours != nil && *s.IntervalRunHours > 0 {
+		w.RunHours = float64(*s.IntervalRunHours) * PMSuppressionWindowFraction
+	}
+	if s.IntervalOdometerKm != nil && *s.IntervalOdometerKm > 0 {
+		w.OdometerKm = float64(*s.IntervalOdometerKm) * PMSuppressionWindowFraction
+	}
+	return w
+}
+
+// DueWithin reports whether the schedule is due, or comes due on any trigger
+// within the window
+func (d PMDueStatus) DueWithin(w PMWindow) bool {
+	if d.Due {
+		return true
+	}
+	return (d.DaysRemaining != nil && *d.DaysRemaining <= w.Days) ||
+		(d.RunHoursRemaining != nil && *d.RunHoursRemaining <= w.RunHours) ||
+		(d.OdometerRemaining != nil && *d.OdometerRemaining <= w.OdometerKm)
+}
+
+// OverdueSchedules returns the schedules past at least one trigger at the given time
+func OverdueSchedules(schedules []PMSchedule, now time.Time) []PMSchedule {
+	overdue := []PMSchedule{}
+	for _, s := range schedules {
+		if s.CheckDue(now).Due {
+			overdue = append(overdue, s)
+		}
+	}
+	return overdue
+}
+
+// remainingUsage returns how much of a usage interval is left since the baseline
+func remainingUsage(current float64, baseline *float64, interval int) float64 {
+	var last float64
+	if baseline != nil {
+		last = *baseline
+	}
+	return float64(interval) - (current - last)
+}
+
+// truncateDay returns midnight UTC of the given day, matching how DATE columns are scanned
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
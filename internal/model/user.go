@@ -14,32 +14,35 @@ type User struct {
 	FullName     string    `json:"fullName"`
 	Role         string    `json:"role"`
 	IsActive     bool      `json:"isActive"`
+	HourlyRate   *float64  `json:"hourlyRate,omitempty"` // Overrides the tenant's labor rate
 	CreatedAt    time.Time `json:"createdAt"`
 }
 
 // UserResponse is the API response for a user (without sensitive data)
 type UserResponse struct {
-	ID        string    `json:"id"`
-	TenantID  string    `json:"tenantId"`
-	OrgUnitID *string   `json:"orgUnitId,omitempty"`
-	Email     string    `json:"email"`
-	FullName  string    `json:"fullName"`
-	Role      string    `json:"role"`
-	IsActive  bool      `json:"isActive"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID         string    `json:"id"`
+	TenantID   string    `json:"tenantId"`
+	OrgUnitID  *string   `json:"orgUnitId,omitempty"`
+	Email      string    `json:"email"`
+	FullName   string    `json:"fullName"`
+	Role       string    `json:"role"`
+	IsActive   bool      `json:"isActive"`
+	HourlyRate *float64  `json:"hourlyRate,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
 }
 
 // ToResponse converts User to UserResponse
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
-		ID:        u.ID,
-		TenantID:  u.TenantID,
-		OrgUnitID: u.OrgUnitID,
-		Email:     u.Email,
-		FullName:  u.FullName,
-		Role:      u.Role,
-		IsActive:  u.IsActive,
-		CreatedAt: u.CreatedAt,
+		ID:         u.ID,
+		TenantID:   u.TenantID,
+		OrgUnitID:  u.OrgUnitID,
+		Email:      u.Email,
+		FullName:   u.FullName,
+		Role:       u.Role,
+		IsActive:   u.IsActive,
+		HourlyRate: u.HourlyRate,
+		CreatedAt:  u.CreatedAt,
 	}
 }
 
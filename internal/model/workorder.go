@@ -6,22 +6,49 @@ import (
 
 // WorkOrder represents a maintenance work order (v1.1 schema)
 type WorkOrder struct {
-	ID             string     `json:"id"`
-	TenantID       string     `json:"tenantId"`
-	ReadableID     *int       `json:"readableId,omitempty"`
-	AssetID        *string    `json:"assetId,omitempty"`
-	AssignedUserID *string    `json:"assignedUserId,omitempty"`
-	Status         string     `json:"status"`
-	Origin         string     `json:"origin"`
-	Priority       string     `json:"priority"`
-	Title          string     `json:"title"`
-	Description    *string    `json:"description,omitempty"`
-	StartedAt      *time.Time `json:"startedAt,omitempty"`
-	CompletedAt    *time.Time `json:"completedAt,omitempty"`
-	CreatedAt      time.Time  `json:"createdAt"`
+	ID                  string     `json:"id"`
+	TenantID            string     `json:"tenantId"`
+	ReadableID          *int       `json:"readableId,omitempty"`
+	AssetID             *string    `json:"assetId,omitempty"`
+	AssignedUserID      *string    `json:"assignedUserId,omitempty"`
+	RequestedByUserID   *string    `json:"requestedByUserId,omitempty"`
+	PMScheduleID        *string    `json:"pmScheduleId,omitempty"`
+	ChecklistTemplateID *string    `json:"checklistTemplateId,omitempty"`
+	ParentWorkOrderID   *string    `json:"parentWorkOrderId,omitempty"`
+	SourceTaskID        *string    `json:"sourceTaskId,omitempty"`
+	ProblemCodeID       *string    `json:"problemCodeId,omitempty"`
+	CauseCodeID         *string    `json:"causeCodeId,omitempty"`
+	RemedyCodeID        *string    `json:"remedyCodeId,omitempty"`
+	CloseoutNotes       *string    `json:"closeoutNotes,omitempty"`
+	Status              string     `json:"status"`
+	Origin              string     `json:"origin"`
+	Priority            string     `json:"priority"`
+	Title               string     `json:"title"`
+	Description         *string    `json:"description,omitempty"`
+	StartedAt           *time.Time `json:"startedAt,omitempty"`
+	CompletedAt         *time.Time `json:"completedAt,omitempty"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	RespondBy           *time.Time `json:"respondBy,omitempty"` // Work must start by
+	ResolveBy           *time.Time `json:"resolveBy,omitempty"` // Work must be complete by
+	EscalationLevel     int        `json:"escalationLevel"`
+	SuppressedPMIDs     []string   `json:"suppressedPmIds,omitempty"` // PM schedules this PM work order covers
 
 	// Computed/Joined fields
-	AssetName string `json:"assetName,omitempty"`
+	Overdue    bool            `json:"overdue"`
+	AssetName  string          `json:"assetName,omitempty"`
+	LaborHours float64         `json:"laborHours"`
+	LaborCost  float64         `json:"laborCost"`
+	FollowUps  []WorkOrderLink `json:"followUps,omitempty"`
+}
+
+// WorkOrderLink is a short reference to a related work order
+type WorkOrderLink struct {
+	ID           string  `json:"id"`
+	ReadableID   *int    `json:"readableId,omitempty"`
+	SourceTaskID *string `json:"sourceTaskId,omitempty"`
+	Status       string  `json:"status"`
+	Priority     string  `json:"priority"`
+	Title        string  `json:"title"`
 }
 
 // WorkOrder Status constants (v1.1 schema)
@@ -34,6 +61,33 @@ const (
 	WOStatusCancelled    = "Cancelled"
 )
 
+// WOTransitions lists the statuses reachable from each work order status.
+// Work_Complete may be sent back to In_Progress when it fails supervisor QC.
+var WOTransitions = map[string][]string{
+	WOStatusRequested:    {WOStatusApproved, WOStatusCancelled},
+	WOStatusApproved:     {WOStatusInProgress, WOStatusCancelled},
+	WOStatusInProgress:   {WOStatusWorkComplete, WOStatusCancelled},
+	WOStatusWorkComplete: {WOStatusClosed, WOStatusInProgress},
+	WOStatusClosed:       {},
+	WOStatusCancelled:    {},
+}
+
+// IsValidWOStatus reports whether status is a known work order status
+func IsValidWOStatus(status string) bool {
+	_, ok := WOTransitions[status]
+	return ok
+}
+
+// CanTransitionWO reports whether a work order may move from one status to another
+func CanTransitionWO(from, to string) bool {
+	for _, next := range WOTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 // WorkOrder Priority constants
 const (
 	WOPriorityLow      = "Low"
@@ -42,6 +96,47 @@ const (
 	WOPriorityCritical = "Critical"
 )
 
+// IsValidWOPriority reports whether p is a known work order priority; SLA
+// deadlines are only defined for these
+func IsValidWOPriority(p string) bool {
+	switch p {
+	case WOPriorityLow, WOPriorityMedium, WOPriorityHigh, WOPriorityCritical:
+		return true
+	}
+	return false
+}
+
+// DefectPriority returns the priority of a defect follow-up raised from a parent work
+// order: it inherits the parent's priority, and a failed mandatory task is at least High.
+func DefectPriority(parentPriority string, mandatory bool) string {
+	if mandatory && (parentPriority == WOPriorityLow || parentPriority == WOPriorityMedium || parentPriority == "") {
+		return WOPriorityHigh
+	}
+	if parentPriority == "" {
+		return WOPriorityMedium
+	}
+	return parentPriority
+}
+
+// EscalatePriority returns the next priority up, capped at Critical
+func EscalatePriority(priority string) string {
+	switch priority {
+	case WOPriorityLow:
+		return WOPriorityMedium
+	case WOPriorityMedium:
+		return WOPriorityHigh
+	default:
+		return WOPriorityCritical
+	}
+}
+
+// SLA escalation levels: each breach escalates a work order once
+const (
+	SLAEscalationNone       = 0
+	SLAEscalationResponse   = 1 // Work not started by respondBy
+	SLAEscalationResolution = 2 // Work not complete by resolveBy
+)
+
 // WorkOrder Origin constants (v1.1 schema)
 const (
 	WOOriginPreventiveAuto = "Preventive_Auto"
@@ -51,12 +146,29 @@ const (
 
 // WorkOrderListParams for filtering and pagination
 type WorkOrderListParams struct {
-	TenantID string
-	Status   []string
-	Priority []string
-	AssetID  string
-	SortBy   string
-	SortDir  string
-	Page     int
-	Limit    int
+	TenantID       string
+	Status         []string
+	Priority       []string
+	AssetID        string
+	AssignedUserID string
+	OpenOnly       bool // Excludes Closed and Cancelled
+	Overdue        bool // Past respondBy or resolveBy
+	SortBy         string
+	SortDir        string
+	Page           int
+	Limit          int
+}
+
+// TechnicianWorkload summarizes the open work assigned to one user
+type TechnicianWorkload struct {
+	UserID       string `json:"userId"`
+	FullName     string `json:"fullName"`
+	Role         string `json:"role"`
+	OpenTotal    int    `json:"openTotal"`
+	Requested    int    `json:"requested"`
+	Approved     int    `json:"approved"`
+	InProgress   int    `json:"inProgress"`
+	WorkComplete int    `json:"workComplete"`
+	Critical     int    `json:"critical"`
+	High         int    `json:"high"`
 }
@@ -0,0 +1,75 @@
+package model
+
+import "testing"
+
+func TestCanTransitionWO(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{WOStatusRequested, WOStatusApproved, true},
+		{WOStatusRequested, WOStatusCancelled, true},
+		{WOStatusApproved, WOStatusInProgress, true},
+		{WOStatusApproved, WOStatusCancelled, true},
+		{WOStatusInProgress, WOStatusWorkComplete, true},
+		{WOStatusInProgress, WOStatusCancelled, true},
+		{WOStatusWorkComplete, WOStatusClosed, true},
+		{WOStatusWorkComplete, WOStatusInProgress, true}, // failed QC
+
+		{WOStatusRequested, WOStatusInProgress, false}, // skips approval
+		{WOStatusRequested, WOStatusClosed, false},
+		{WOStatusApproved, WOStatusWorkComplete, false},
+		{WOStatusInProgress, WOStatusClosed, false}, // skips QC
+		{WOStatusInProgress, WOStatusApproved, false},
+		{WOStatusWorkComplete, WOStatusCancelled, false},
+		{WOStatusClosed, WOStatusInProgress, false},
+		{WOStatusCancelled, WOStatusRequested, false},
+		{WOStatusRequested, WOStatusRequested, false},
+		{"Open", WOStatusApproved, false},
+		{WOStatusRequested, "Done", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionWO(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionWO(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestEscalatePriority(t *testing.T) {
+	tests := []struct {
+		priority, want string
+	}{
+		{WOPriorityLow, WOPriorityMedium},
+		{WOPriorityMedium, WOPriorityHigh},
+		{WOPriorityHigh, WOPriorityCritical},
+		{WOPriorityCritical, WOPriorityCritical},
+	}
+
+	for _, tt := range tests {
+		if got := EscalatePriority(tt.priority); got != tt.want {
+			t.Errorf("EscalatePriority(%q) = %q, want %q", tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidWOPriority(t *testing.T) {
+	tests := []struct {
+		priority string
+		want     bool
+	}{
+		{WOPriorityLow, true},
+		{WOPriorityMedium, true},
+		{WOPriorityHigh, true},
+		{WOPriorityCritical, true},
+		{"", false},
+		{"critical", false},
+		{"Urgent", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidWOPriority(tt.priority); got != tt.want {
+			t.Errorf("IsValidWOPriority(%q) = %v, want %v", tt.priority, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"ioi-amms/internal/model"
+)
+
+// BriefPDF renders the Monday Morning Brief, addressed to recipient when given
+func BriefPDF(b *model.WeeklyBrief, recipient string) ([]byte, error) {
+	lastDay := b.WeekEnd.AddDate(0, 0, -1)
+	d := newDocument("Monday Morning Brief - "+b.TenantName,
+		fmt.Sprintf("%s - generated %s", b.TenantName, formatDateTime(b.GeneratedAt)))
+
+	subtitle := fmt.Sprintf("%s  |  Week of %s - %s", b.TenantName, formatDate(b.WeekStart), formatDate(lastDay))
+	if recipient != "" {
+		subtitle += "  |  Prepared for " + recipient
+	}
+	d.title("Monday Morning Brief", subtitle)
+
+	// KPIs
+	d.section("Key figures")
+	mttr := "-"
+	if b.Reliability.MTTRHours != nil {
+		mttr = fmt.Sprintf("%.1f h", *b.Reliability.MTTRHours)
+	}
+	d.tiles(
+		[]string{"WOs opened", "WOs completed", "Open WOs", "Completion rate", "Critical down"},
+		[]string{
+			fmt.Sprint(b.Opened), fmt.Sprint(b.Completed), fmt.Sprint(b.Stats.OpenWorkOrders),
+			fmt.Sprintf("%d%%", b.Stats.CompletionRate), fmt.Sprint(b.Stats.CriticalDown),
+		},
+		5,
+	)
+	d.tiles(
+		[]string{"Overdue PMs", "Availability", "MTTR", "Failures", "Meter data health"},
+		[]string{
+			fmt.Sprint(len(b.OverduePMs)), fmt.Sprintf("%.1f%%", b.Reliability.AvailabilityPct), mttr,
+			fmt.Sprint(b.Reliability.Failures), fmt.Sprintf("%.0f%%", b.Stats.DataHealthPct),
+		},
+		5,
+	)
+	d.note("Availability, MTTR and failures cover last week; other figures are as of generation.")
+
+	// Completed work orders
+	d.section(fmt.Sprintf("Completed last week (%d)", b.Completed))
+	if len(b.CompletedWorkOrders) == 0 {
+		d.note("No work orders were completed.")
+	} else {
+		rows := make([][]string, 0, len(b.CompletedWorkOrders))
+		for _, wo := range b.CompletedWorkOrders {
+			rows = append(rows, []string{workOrderRef(wo), wo.Title, wo.Priority, strings.ReplaceAll(wo.Status, "_", " ")})
+		}
+		d.table([]string{"WO", "Title", "Priority", "Status"}, []float64{0.12, 0.58, 0.14, 0.16}, rows)
+		if b.Completed > len(b.CompletedWorkOrders) {
+			d.note(fmt.Sprintf("Showing the latest %d of %d.", len(b.CompletedWorkOrders), b.Completed))
+		}
+	}
+
+	// Overdue PMs
+	d.section(fmt.Sprintf("Overdue preventive maintenance (%d)", len(b.OverduePMs)))
+	if len(b.OverduePMs) == 0 {
+		d.note("All PM schedules are up to date.")
+	} else {
+		rows := make([][]string, 0, len(b.OverduePMs))
+		for _, s := range b.OverduePMs {
+			status := s.CheckDue(b.GeneratedAt)
+			wo := "Not raised"
+			if s.OpenWorkOrderID != nil {
+				wo = "Raised"
+			}
+			rows = append(rows, []string{s.AssetName, s.Title, strings.Join(status.Triggers, ", "), wo})
+		}
+		d.table([]string{"Asset", "Schedule", "Triggered by", "Work order"}, []float64{0.3, 0.36, 0.18, 0.16}, rows)
+	}
+
+	// Critical assets
+	d.section(fmt.Sprintf("Critical assets (%d)", len(b.CriticalAssets)))
+	if len(b.CriticalAssets) == 0 {
+		d.note("No assets are Down or Red Tagged.")
+	} else {
+		rows := make([][]string, 0, len(b.CriticalAssets))
+		for _, a := range b.CriticalAssets {
+			since := "-"
+			if a.DownSince != nil {
+				since = formatDateTime(*a.DownSince)
+			}
+			rows = append(rows, []string{a.AssetName, strings.ReplaceAll(a.Status, "_", " "), since, fmt.Sprintf("%.1f", a.DownHours)})
+		}
+		d.table([]string{"Asset", "Status", "Down since", "Hours down"}, []float64{0.4, 0.15, 0.3, 0.15}, rows)
+	}
+
+	// Low stock
+	d.section(fmt.Sprintf("Low stock (%d)", b.LowStockTotal))
+	if len(b.LowStock) == 0 {
+		d.note("No stock is below its minimum level.")
+	} else {
+		rows := make([][]string, 0, len(b.LowStock))
+		for _, s := range b.LowStock {
+			rows = append(rows, []string{
+				s.PartName, s.PartSKU, s.LocationName,
+				fmt.Sprintf("%g", s.QuantityOnHand), fmt.Sprintf("%g", s.MinStockLevel),
+			})
+		}
+		d.table([]string{"Part", "SKU", "Location", "On hand", "Minimum"}, []float64{0.32, 0.16, 0.28, 0.12, 0.12}, rows)
+		if b.LowStockTotal > len(b.LowStock) {
+			d.note(fmt.Sprintf("Showing %d of %d.", len(b.LowStock), b.LowStockTotal))
+		}
+	}
+
+	return d.bytes()
+}
+
+// workOrderRef returns a work order's readable reference, e.g. "WO-42"
+func workOrderRef(wo model.WorkOrderLink) string {
+	if wo.ReadableID != nil {
+		return fmt.Sprintf("WO-%d", *wo.ReadableID)
+	}
+	return wo.ID[:8]
+}
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"ioi-amms/internal/model"
+)
+
+// masterQRSize is the side of the gate pass's master QR code, in millimetres
+const masterQRSize = 38.0
+
+// GatePassPDF renders a shipment's gate pass: the manifest of every asset with its
+// identities, and a master QR code of the shipment reference for bulk receiving
+func GatePassPDF(g *model.GatePass) ([]byte, error) {
+	first := g.Items[0]
+	d := newDocument("Gate Pass "+g.ShipmentRef+" - "+g.TenantName,
+		fmt.Sprintf("%s - gate pass %s - generated %s", g.TenantName, g.ShipmentRef, formatDateTime(g.GeneratedAt)))
+
+	// Master QR in the top right corner, beside the title
+	top := d.pdf.GetY()
+	qrX := pageMargin + contentWidth - masterQRSize
+	if err := d.qrCode(g.ShipmentRef, qrX, top, masterQRSize); err != nil {
+		return nil, err
+	}
+	d.pdf.SetXY(qrX, top+masterQRSize)
+	d.pdf.SetFont("Helvetica", "", 7)
+	d.pdf.SetTextColor(90, 90, 90)
+	d.pdf.CellFormat(masterQRSize, 4, "Scan to receive all", "", 0, "C", false, 0, "")
+	d.pdf.SetXY(pageMargin, top)
+
+	d.title("Gate Pass", fmt.Sprintf("%s  |  %s", g.TenantName, g.ShipmentRef))
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.SetTextColor(30, 30, 30)
+	details := [][2]string{
+		{"To", first.ToLocationName},
+		{"Transfer type", first.TransferType},
+		{"Requested by", first.CreatedByName},
+		{"Requested", formatDateTime(first.CreatedAt)},
+		{"Issued by", g.GeneratedBy},
+	}
+	for _, kv := range details {
+		if kv[1] == "" {
+			kv[1] = "-"
+		}
+		d.pdf.SetFont("Helvetica", "B", 10)
+		d.pdf.CellFormat(30, 6, d.tr(kv[0]), "", 0, "L", false, 0, "")
+		d.pdf.SetFont("Helvetica", "", 10)
+		d.pdf.CellFormat(contentWidth-masterQRSize-35, 6, d.fit(kv[1], contentWidth-masterQRSize-37), "", 1, "L", false, 0, "")
+	}
+	if y := top + masterQRSize + 6; d.pdf.GetY() < y {
+		d.pdf.SetY(y)
+	}
+	if first.Notes != nil && *first.Notes != "" {
+		d.note("Notes: " + *first.Notes)
+	}
+
+	// Manifest: one row per identity, the asset named on its first
+	d.section(fmt.Sprintf("Manifest (%d assets)", len(g.Items)))
+	rows := make([][]string, 0, len(g.Items))
+	for n, item := range g.Items {
+		from := item.FromLocationName
+		if from == "" {
+			from = "-"
+		}
+		if len(item.Identities) == 0 {
+			rows = append(rows, []string{fmt.Sprint(n + 1), item.AssetName, "-", "-", from, statusLabel(item.Status)})
+			continue
+		}
+		for i, id := range item.Identities {
+			kind := strings.ReplaceAll(id.Type, "_", " ")
+			if id.IsPrimary {
+				kind += " *"
+			}
+			if i == 0 {
+				rows = append(rows, []string{fmt.Sprint(n + 1), item.AssetName, kind, id.Value, from, statusLabel(item.Status)})
+			} else {
+				rows = append(rows, []string{"", "", kind, id.Value, "", ""})
+			}
+		}
+	}
+	d.table([]string{"#", "Asset", "Identity", "Value", "From", "Status"},
+		[]float64{0.05, 0.27, 0.15, 0.21, 0.19, 0.13}, rows)
+	d.note("* Primary identity. Scan the master QR to receive every item; report missing items as exceptions.")
+
+	// Sign-off
+	d.section("Sign-off")
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.SetTextColor(30, 30, 30)
+	for _, label := range []string{"Released by", "Received by"} {
+		d.pdf.Ln(4)
+		d.pdf.CellFormat(30, 8, d.tr(label), "", 0, "L", false, 0, "")
+		d.pdf.CellFormat(70, 8, "", "B", 0, "L", false, 0, "")
+		d.pdf.CellFormat(20, 8, "Date", "", 0, "R", false, 0, "")
+		d.pdf.CellFormat(40, 8, "", "B", 1, "L", false, 0, "")
+	}
+
+	return d.bytes()
+}
+
+// statusLabel spells out a status, e.g. "In Transit"
+func statusLabel(status string) string {
+	return strings.ReplaceAll(status, "_", " ")
+}
@@ -0,0 +1,173 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// Page layout (A4 portrait, millimetres)
+const (
+	pageMargin   = 15.0
+	contentWidth = 210.0 - 2*pageMargin
+	rowHeight    = 6.0
+)
+
+// document wraps an fpdf document with the shared header, footer and table styles.
+// Text is translated from UTF-8 to the core fonts' code page.
+type document struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+// newDocument starts an A4 document whose pages carry footer on the left and
+// the page number on the right
+func newDocument(title, footer string) *document {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("IOI AMMS", true)
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin+5)
+	pdf.AliasNbPages("")
+
+	d := &document{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(contentWidth/2, 5, d.tr(footer), "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth/2, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+	return d
+}
+
+// title writes the document title and a subtitle line
+func (d *document) title(title, subtitle string) {
+	d.pdf.SetFont("Helvetica", "B", 18)
+	d.pdf.SetTextColor(20, 40, 80)
+	d.pdf.CellFormat(contentWidth, 9, d.tr(title), "", 1, "L", false, 0, "")
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.SetTextColor(90, 90, 90)
+	d.pdf.CellFormat(contentWidth, 6, d.tr(subtitle), "", 1, "L", false, 0, "")
+	d.pdf.Ln(3)
+}
+
+// section writes a section heading
+func (d *document) section(heading string) {
+	d.pdf.Ln(3)
+	d.pdf.SetFont("Helvetica", "B", 12)
+	d.pdf.SetTextColor(20, 40, 80)
+	d.pdf.CellFormat(contentWidth, 8, d.tr(heading), "B", 1, "L", false, 0, "")
+	d.pdf.Ln(1)
+}
+
+// note writes a line of muted text
+func (d *document) note(text string) {
+	d.pdf.SetFont("Helvetica", "I", 9)
+	d.pdf.SetTextColor(110, 110, 110)
+	d.pdf.MultiCell(contentWidth, 5, d.tr(text), "", "L", false)
+}
+
+// tiles writes label/value pairs as a row of boxes, wrapping every perRow tiles
+func (d *document) tiles(labels, values []string, perRow int) {
+	w := contentWidth / float64(perRow)
+	for i := range labels {
+		if i > 0 && i%perRow == 0 {
+			d.pdf.Ln(18)
+		}
+		x, y := d.pdf.GetXY()
+		d.pdf.SetFillColor(240, 244, 250)
+		d.pdf.Rect(x+1, y, w-2, 16, "F")
+		d.pdf.SetXY(x+1, y+1.5)
+		d.pdf.SetFont("Helvetica", "B", 14)
+		d.pdf.SetTextColor(20, 40, 80)
+		d.pdf.CellFormat(w-2, 7, d.tr(values[i]), "", 2, "C", false, 0, "")
+		d.pdf.SetFont("Helvetica", "", 8)
+		d.pdf.SetTextColor(90, 90, 90)
+		d.pdf.CellFormat(w-2, 5, d.tr(labels[i]), "", 0, "C", false, 0, "")
+		d.pdf.SetXY(x+w, y)
+	}
+	d.pdf.Ln(20)
+}
+
+// table writes a header row and data rows; widths are fractions of the content width.
+// Cell text is truncated to fit, and the header repeats after a page break.
+func (d *document) table(headers []string, widths []float64, rows [][]string) {
+	header := func() {
+		d.pdf.SetFont("Helvetica", "B", 9)
+		d.pdf.SetFillColor(20, 40, 80)
+		d.pdf.SetTextColor(255, 255, 255)
+		for i, h := range headers {
+			d.pdf.CellFormat(widths[i]*contentWidth, rowHeight+1, d.tr(h), "", 0, "L", true, 0, "")
+		}
+		d.pdf.Ln(-1)
+	}
+	header()
+
+	_, pageHeight := d.pdf.GetPageSize()
+	_, _, _, bottom := d.pdf.GetMargins()
+	d.pdf.SetFont("Helvetica", "", 9)
+	d.pdf.SetTextColor(30, 30, 30)
+	for n, row := range rows {
+		if d.pdf.GetY()+rowHeight > pageHeight-bottom {
+			d.pdf.AddPage()
+			header()
+			d.pdf.SetFont("Helvetica", "", 9)
+			d.pdf.SetTextColor(30, 30, 30)
+		}
+		fill := n%2 == 1
+		d.pdf.SetFillColor(245, 245, 245)
+		for i, cell := range row {
+			w := widths[i] * contentWidth
+			d.pdf.CellFormat(w, rowHeight, d.fit(cell, w-2), "", 0, "L", fill, 0, "")
+		}
+		d.pdf.Ln(-1)
+	}
+}
+
+// fit translates s and truncates it with an ellipsis to fit within width
+func (d *document) fit(s string, width float64) string {
+	t := d.tr(s)
+	if d.pdf.GetStringWidth(t) <= width {
+		return t
+	}
+	for len(t) > 0 && d.pdf.GetStringWidth(t+"...") > width {
+		t = t[:len(t)-1]
+	}
+	return t + "..."
+}
+
+// qrCode draws a QR code of content as a size x size square at (x, y)
+func (d *document) qrCode(content string, x, y, size float64) error {
+	png, err := qrcode.Encode(content, qrcode.Medium, 512)
+	if err != nil {
+		return err
+	}
+	opts := fpdf.ImageOptions{ImageType: "PNG"}
+	d.pdf.RegisterImageOptionsReader("qr:"+content, opts, bytes.NewReader(png))
+	d.pdf.ImageOptions("qr:"+content, x, y, size, size, false, opts, 0, "")
+	return d.pdf.Error()
+}
+
+// bytes renders the document
+func (d *document) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatDate formats a date as e.g. "6 Jan 2025"
+func formatDate(t time.Time) string {
+	return t.Format("2 Jan 2006")
+}
+
+// formatDateTime formats a timestamp in UTC as e.g. "6 Jan 2025 14:05 UTC"
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format("2 Jan 2006 15:04") + " UTC"
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"math"
 	"time"
 
 	"ioi-amms/internal/model"
@@ -17,74 +18,105 @@ func NewAnalyticsRepository(db *pgxpool.Pool) *AnalyticsRepository {
 	return &AnalyticsRepository{db: db}
 }
 
-// GetDashboardStats aggregates counts for the dashboard
+// inServiceStatuses are asset statuses expected to report meter readings
+var inServiceStatuses = []string{model.AssetStatusActive, model.AssetStatusDown, model.AssetStatusRedTag}
+
+// downStatuses are asset statuses shown on the Critical Down tile
+var downStatuses = []string{model.AssetStatusDown, model.AssetStatusRedTag}
+
+// walletValueQuery totals each user's wallet value for tenant $1 and keeps those
+// above the tenant's walletWatchThreshold, defaulting to $2
+const walletValueQuery = `
+	SELECT u.id, COALESCE(u.full_name, u.email),
+		SUM(w.qty_held * COALESCE(p.unit_cost, 0)) AS total_value,
+		COALESCE(SUM(w.qty_held) FILTER (WHERE p.unit_cost IS NULL), 0) AS unpriced_qty
+	FROM inventory_wallets w
+	JOIN users u ON u.id = w.user_id
+	JOIN parts p ON p.id = w.part_id
+	WHERE u.tenant_id = $1 AND w.qty_held > 0
+	GROUP BY u.id, u.full_name, u.email
+	HAVING SUM(w.qty_held * COALESCE(p.unit_cost, 0)) > (
+		SELECT COALESCE((settings->>'walletWatchThreshold')::float8, $2) FROM tenants WHERE id = $1
+	)
+`
+
+// GetDashboardStats aggregates counts for the dashboard. OverduePMs is left for the
+// caller, since PM due status is evaluated in Go.
 func (r *AnalyticsRepository) GetDashboardStats(ctx context.Context, tenantID string) (*model.DashboardStats, error) {
 	stats := &model.DashboardStats{}
 
-	// 1. Total Assets
-	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM assets WHERE tenant_id = $1", tenantID).Scan(&stats.TotalAssets)
+	// Assets: total, down, and meter data health for those in service
+	var inService, freshReadings int
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE a.status = ANY($2)),
+			COUNT(*) FILTER (WHERE a.status = ANY($3)),
+			COUNT(*) FILTER (WHERE a.status = ANY($3) AND m.last_updated_at >= NOW() - make_interval(days => $4))
+		FROM assets a
+		LEFT JOIN asset_meters m ON m.asset_id = a.id
+		WHERE a.tenant_id = $1
+	`, tenantID, downStatuses, inServiceStatuses, model.DataHealthWindowDays).Scan(
+		&stats.TotalAssets, &stats.CriticalDown, &inService, &freshReadings)
 	if err != nil {
 		return nil, err
 	}
+	stats.CriticalAlerts = stats.CriticalDown
 
-	// 2. Open Work Orders (Anything not Completed or Cancelled)
-	err = r.db.QueryRow(ctx,
-		"SELECT COUNT(*) FROM work_orders WHERE tenant_id = $1 AND status NOT IN ('Completed', 'Cancelled')",
-		tenantID).Scan(&stats.OpenWorkOrders)
-	if err != nil {
-		return nil, err
+	stats.DataHealthPct = 100 // Nothing in service means nothing is missing
+	if inService > 0 {
+		stats.DataHealthPct = math.Round(float64(freshReadings)*1000/float64(inService)) / 10
 	}
 
-	// 3. Maintenance Due (Work orders with 'High' or 'Critical' priority that are open)
-	err = r.db.QueryRow(ctx,
-		"SELECT COUNT(*) FROM work_orders WHERE tenant_id = $1 AND status NOT IN ('Completed', 'Cancelled') AND priority IN ('High', 'Critical')",
-		tenantID).Scan(&stats.MaintenanceDue)
+	// Work orders: open is anything not Closed or Cancelled; faults are corrective work
+	var finished, total int
+	err = r.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status NOT IN ($2, $3)),
+			COUNT(*) FILTER (WHERE status NOT IN ($2, $3) AND priority IN ($4, $5)),
+			COUNT(*) FILTER (WHERE status NOT IN ($2, $3) AND origin <> $6),
+			COUNT(*) FILTER (WHERE status IN ($2, $7)),
+			COUNT(*) FILTER (WHERE status <> $3)
+		FROM work_orders
+		WHERE tenant_id = $1
+	`, tenantID, model.WOStatusClosed, model.WOStatusCancelled,
+		model.WOPriorityHigh, model.WOPriorityCritical, model.WOOriginPreventiveAuto,
+		model.WOStatusWorkComplete,
+	).Scan(&stats.OpenWorkOrders, &stats.MaintenanceDue, &stats.OpenFaults, &finished, &total)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. Critical Alerts (Assets with 'Critical' status)
-	err = r.db.QueryRow(ctx,
-		"SELECT COUNT(*) FROM assets WHERE tenant_id = $1 AND status = 'Critical'",
-		tenantID).Scan(&stats.CriticalAlerts)
-	if err != nil {
-		return nil, err
+	// Completion Rate (finished / non-cancelled work orders)
+	if total > 0 {
+		stats.CompletionRate = (finished * 100) / total
+	} else {
+		stats.CompletionRate = 100 // Default to 100% if no work orders exist yet
 	}
 
-	// 5. Completion Rate (Completed WOs / Total WOs)
-	var totalWOs, completedWOs int
-	err = r.db.QueryRow(ctx, "SELECT COUNT(*) FROM work_orders WHERE tenant_id = $1", tenantID).Scan(&totalWOs)
+	// Wallet Watch
+	err = r.db.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(total_value), 0) FROM (`+walletValueQuery+`) ww
+	`, tenantID, model.DefaultWalletWatchThreshold).Scan(&stats.WalletWatch, &stats.WalletWatchValue)
 	if err != nil {
 		return nil, err
 	}
 
-	if totalWOs > 0 {
-		err = r.db.QueryRow(ctx, "SELECT COUNT(*) FROM work_orders WHERE tenant_id = $1 AND status = 'Completed'", tenantID).Scan(&completedWOs)
-		if err != nil {
-			return nil, err
-		}
-		stats.CompletionRate = (completedWOs * 100) / totalWOs
-	} else {
-		stats.CompletionRate = 100 // Default to 100% if no work orders exist yet
-	}
-
-	stats.OpenFaults = stats.CriticalAlerts // Mapping "Faults" to Critical Assets for now
-
 	return stats, nil
 }
 
 // GetTopAlerts returns a list of critical items for the dashboard feed
 func (r *AnalyticsRepository) GetTopAlerts(ctx context.Context, tenantID string) ([]model.Alert, error) {
-	// For now, we generate alerts based on Critical assets
+	// For now, we generate alerts based on assets that are Down or Red_Tag
 	query := `
-		SELECT id, name, updated_at 
-		FROM assets 
-		WHERE tenant_id = $1 AND status = 'Critical'
+		SELECT id, name, status, updated_at
+		FROM assets
+		WHERE tenant_id = $1 AND status = ANY($2)
 		ORDER BY updated_at DESC
 		LIMIT 5
 	`
 
-	rows, err := r.db.Query(ctx, query, tenantID)
+	rows, err := r.db.Query(ctx, query, tenantID, downStatuses)
 	if err != nil {
 		return nil, err
 	}
@@ -92,15 +124,20 @@ func (r *AnalyticsRepository) GetTopAlerts(ctx context.Context, tenantID string)
 
 	var alerts []model.Alert
 	for rows.Next() {
-		var id, name string
+		var id, name, status string
 		var updatedAt time.Time
-		if err := rows.Scan(&id, &name, &updatedAt); err != nil {
+		if err := rows.Scan(&id, &name, &status, &updatedAt); err != nil {
 			return nil, err
 		}
 
+		title := "Asset Down"
+		if status == model.AssetStatusRedTag {
+			title = "Asset Red Tagged"
+		}
+
 		alerts = append(alerts, model.Alert{
 			ID:        id,
-			Title:     "Asset Reported Critical Status",
+			Title:     title,
 			Severity:  "Critical",
 			AssetID:   id,
 			AssetName: name,
@@ -108,5 +145,427 @@ func (r *AnalyticsRepository) GetTopAlerts(ctx context.Context, tenantID string)
 		})
 	}
 
-	return alerts, nil
+	return alerts, rows.Err()
+}
+
+// ListCriticalDown returns the tenant's Down and Red_Tag assets with their open
+// downtime event, longest down first
+func (r *AnalyticsRepository) ListCriticalDown(ctx context.Context, tenantID string) ([]model.CriticalDownAsset, error) {
+	query := `
+		SELECT a.id, a.name, a.status, a.location_id, d.started_at, d.work_order_id,
+			COALESCE(EXTRACT(EPOCH FROM (NOW()::timestamp - d.started_at)) / 3600, 0)
+		FROM assets a
+		LEFT JOIN asset_downtime d ON d.asset_id = a.id AND d.ended_at IS NULL
+		WHERE a.tenant_id = $1 AND a.status = ANY($2)
+		ORDER BY d.started_at ASC NULLS LAST, a.name
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, downStatuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assets := []model.CriticalDownAsset{}
+	for rows.Next() {
+		var a model.CriticalDownAsset
+		if err := rows.Scan(&a.AssetID, &a.AssetName, &a.Status, &a.LocationID,
+			&a.DownSince, &a.WorkOrderID, &a.DownHours); err != nil {
+			return nil, err
+		}
+		assets = append(assets, a)
+	}
+
+	return assets, rows.Err()
+}
+
+// ListWalletWatch returns users whose wallet value exceeds the tenant's threshold,
+// highest value first, with their holdings
+func (r *AnalyticsRepository) ListWalletWatch(ctx context.Context, tenantID string) ([]model.WalletWatchEntry, error) {
+	rows, err := r.db.Query(ctx, walletValueQuery+" ORDER BY total_value DESC",
+		tenantID, model.DefaultWalletWatchThreshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.WalletWatchEntry{}
+	index := map[string]int{}
+	for rows.Next() {
+		e := model.WalletWatchEntry{Holdings: []model.InventoryWallet{}}
+		if err := rows.Scan(&e.UserID, &e.UserName, &e.TotalValue, &e.UnpricedQty); err != nil {
+			return nil, err
+		}
+		index[e.UserID] = len(entries)
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	userIDs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		userIDs = append(userIDs, e.UserID)
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT w.user_id, w.part_id, w.qty_held, w.last_updated_at, p.name, p.sku, p.unit_cost
+		FROM inventory_wallets w
+		JOIN parts p ON p.id = w.part_id
+		WHERE w.user_id::text = ANY($1) AND w.qty_held > 0
+		ORDER BY w.qty_held * COALESCE(p.unit_cost, 0) DESC, p.name
+	`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var h model.InventoryWallet
+		if err := rows.Scan(&h.UserID, &h.PartID, &h.QtyHeld, &h.LastUpdatedAt,
+			&h.PartName, &h.PartSKU, &h.UnitCost); err != nil {
+			return nil, err
+		}
+		i := index[h.UserID]
+		h.UserName = entries[i].UserName
+		entries[i].Holdings = append(entries[i].Holdings, h)
+	}
+
+	return entries, rows.Err()
+}
+
+// ListStaleMeters returns in-service assets without a meter reading in the data
+// health window, never-read assets first
+func (r *AnalyticsRepository) ListStaleMeters(ctx context.Context, tenantID string) ([]model.DataHealthAsset, error) {
+	query := `
+		SELECT a.id, a.name, a.status, m.last_updated_at
+		FROM assets a
+		LEFT JOIN asset_meters m ON m.asset_id = a.id
+		WHERE a.tenant_id = $1 AND a.status = ANY($2)
+			AND (m.last_updated_at IS NULL OR m.last_updated_at < NOW() - make_interval(days => $3))
+		ORDER BY m.last_updated_at ASC NULLS FIRST, a.name
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, inServiceStatuses, model.DataHealthWindowDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assets := []model.DataHealthAsset{}
+	for rows.Next() {
+		var a model.DataHealthAsset
+		if err := rows.Scan(&a.AssetID, &a.AssetName, &a.Status, &a.LastReadingAt); err != nil {
+			return nil, err
+		}
+		assets = append(assets, a)
+	}
+
+	return assets, rows.Err()
+}
+
+// reliabilityGroup describes how assets are grouped in the reliability query
+type reliabilityGroup struct {
+	key   string
+	label string
+	join  string
+}
+
+var reliabilityGroups = map[string]reliabilityGroup{
+	model.ReliabilityByAsset: {key: "p.id::text", label: "p.name"},
+	model.ReliabilityByModel: {
+		key:   "COALESCE(p.manufacturer, '') || '|' || COALESCE(p.model_number, '')",
+		label: "COALESCE(NULLIF(TRIM(COALESCE(p.manufacturer, '') || ' ' || COALESCE(p.model_number, '')), ''), 'Unspecified')",
+	},
+	model.ReliabilityByLocation: {
+		key:   "COALESCE(p.location_id::text, '')",
+		label: "COALESCE(l.name, 'Unassigned')",
+		join:  "LEFT JOIN locations l ON l.id = p.location_id",
+	},
+	model.ReliabilityByOrgUnit: {
+		key:   "COALESCE(p.org_unit_id::text, '')",
+		label: "COALESCE(o.name, 'Unassigned')",
+		join:  "LEFT JOIN org_units o ON o.id = p.org_unit_id",
+	},
+}
+
+// IsValidReliabilityGroup reports whether groupBy is a supported grouping
+func IsValidReliabilityGroup(groupBy string) bool {
+	_, ok := reliabilityGroups[groupBy]
+	return ok
+}
+
+// GetReliability computes reliability components per group over [From, To) for the
+// tenant's non-Draft assets in scope, most failures first. Location and org unit
+// filters include their whole subtree.
+func (r *AnalyticsRepository) GetReliability(ctx context.Context, params model.ReliabilityParams) ([]model.ReliabilityMetrics, error) {
+	group, ok := reliabilityGroups[params.GroupBy]
+	if !ok {
+		group = reliabilityGroups[model.ReliabilityByAsset]
+	}
+
+	// Corrective work orders on asset a
+	const corrective = `w.asset_id = a.id AND w.origin <> 'Preventive_Auto' AND w.status <> 'Cancelled'`
+
+	query := `
+		WITH RECURSIVE location_scope AS (
+			SELECT id FROM locations WHERE id::text = $4 AND tenant_id = $1
+			UNION ALL
+			SELECT l.id FROM locations l JOIN location_scope s ON l.parent_id = s.id
+		),
+		org_scope AS (
+			SELECT id FROM org_units WHERE id::text = $5 AND tenant_id = $1
+			UNION ALL
+			SELECT o.id FROM org_units o JOIN org_scope s ON o.parent_id = s.id
+		),
+		per_asset AS (
+			SELECT a.id, a.name, a.manufacturer, a.model_number, a.location_id, a.org_unit_id,
+				GREATEST(0, EXTRACT(EPOCH FROM (
+					LEAST($3::timestamp, NOW()::timestamp) - GREATEST($2::timestamp, a.created_at)
+				)) / 3600) AS exposure_hours,
+				(SELECT COUNT(*) FROM work_orders w
+					WHERE ` + corrective + ` AND w.created_at >= $2 AND w.created_at < $3) AS failures,
+				(SELECT COUNT(*) FROM work_orders w
+					WHERE ` + corrective + ` AND w.completed_at >= $2 AND w.completed_at < $3) AS repairs,
+				(SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (w.completed_at - w.created_at)) / 3600), 0) FROM work_orders w
+					WHERE ` + corrective + ` AND w.completed_at >= $2 AND w.completed_at < $3) AS repair_hours,
+				(SELECT COALESCE(SUM(` + downtimeHoursExpr + `), 0) FROM asset_downtime d
+					WHERE d.asset_id = a.id AND ` + downtimeOverlapCond + `) AS downtime_hours
+			FROM assets a
+			WHERE a.tenant_id = $1 AND a.status <> 'Draft'
+				AND ($4 = '' OR a.location_id IN (SELECT id FROM location_scope))
+				AND ($5 = '' OR a.org_unit_id IN (SELECT id FROM org_scope))
+				AND ($6 = '' OR a.id::text = $6)
+		)
+		SELECT ` + group.key + `, ` + group.label + `, COUNT(*),
+			SUM(p.failures), SUM(p.repairs), SUM(p.exposure_hours), SUM(p.downtime_hours), SUM(p.repair_hours)
+		FROM per_asset p
+		` + group.join + `
+		GROUP BY 1, 2
+		ORDER BY 4 DESC, 2
+	`
+
+	rows, err := r.db.Query(ctx, query, params.TenantID, params.From, params.To,
+		params.LocationID, params.OrgUnitID, params.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	metrics := []model.ReliabilityMetrics{}
+	for rows.Next() {
+		var m model.ReliabilityMetrics
+		err := rows.Scan(&m.Key, &m.Label, &m.Assets,
+			&m.Failures, &m.Repairs, &m.ExposureHours, &m.DowntimeHours, &m.RepairHours)
+		if err != nil {
+			return nil, err
+		}
+		m.Compute()
+		metrics = append(metrics, m)
+	}
+
+	return metrics, rows.Err()
+}
+
+// badActorSorts maps bad actor sort metrics to aggregates over per_asset p
+var badActorSorts = map[string]string{
+	model.BadActorByTotalCost:  "SUM(p.labor_cost + p.parts_cost)",
+	model.BadActorByLaborHours: "SUM(p.labor_hours)",
+	model.BadActorByLaborCost:  "SUM(p.labor_cost)",
+	model.BadActorByPartsCost:  "SUM(p.parts_cost)",
+	model.BadActorByWorkOrders: "SUM(p.work_orders)",
+	model.BadActorByDowntime:   "SUM(p.downtime_hours)",
+}
+
+// IsValidBadActorSort reports whether sortBy is a supported bad actor metric
+func IsValidBadActorSort(sortBy string) bool {
+	_, ok := badActorSorts[sortBy]
+	return ok
+}
+
+// GetBadActors ranks groups of the tenant's non-Draft assets by what they consumed
+// over [From, To): non-cancelled work orders raised, labor performed, parts used
+// (at their cost when consumed) and downtime. Groups with no activity are left out.
+// Returns the top Limit groups and the number of groups ranked.
+func (r *AnalyticsRepository) GetBadActors(ctx context.Context, params model.BadActorParams) ([]model.BadActor, int, error) {
+	group, ok := reliabilityGroups[params.GroupBy]
+	if !ok {
+		group = reliabilityGroups[model.ReliabilityByAsset]
+	}
+	sort, ok := badActorSorts[params.SortBy]
+	if !ok {
+		sort = badActorSorts[model.BadActorByTotalCost]
+	}
+
+	query := `
+		WITH per_asset AS (
+			SELECT a.id, a.name, a.manufacturer, a.model_number, a.location_id, a.org_unit_id,
+				(SELECT COUNT(*) FROM work_orders w
+					WHERE w.asset_id = a.id AND w.status <> 'Cancelled'
+						AND w.created_at >= $2 AND w.created_at < $3) AS work_orders,
+				labor.hours AS labor_hours,
+				labor.cost AS labor_cost,
+				(SELECT COALESCE(SUM(rl.quantity * COALESCE(rl.unit_cost, pt.unit_cost, 0)), 0)
+					FROM wo_resource_logs rl
+					JOIN work_orders w ON w.id = rl.work_order_id
+					LEFT JOIN parts pt ON pt.id = rl.part_id
+					WHERE w.asset_id = a.id AND rl.created_at >= $2 AND rl.created_at < $3) AS parts_cost,
+				(SELECT COALESCE(SUM(` + downtimeHoursExpr + `), 0) FROM asset_downtime d
+					WHERE d.asset_id = a.id AND ` + downtimeOverlapCond + `) AS downtime_hours
+			FROM assets a
+			CROSS JOIN LATERAL (
+				SELECT COALESCE(SUM(l.hours_spent), 0) AS hours,
+					COALESCE(SUM(l.hours_spent * ` + laborRateExpr + `), 0) AS cost
+				FROM wo_labor_logs l
+				JOIN work_orders w ON w.id = l.work_order_id
+				JOIN users u ON u.id = l.user_id
+				JOIN tenants t ON t.id = u.tenant_id
+				WHERE w.asset_id = a.id AND l.date_performed >= $2::timestamp::date AND l.date_performed < $3::timestamp::date
+			) labor
+			WHERE a.tenant_id = $1 AND a.status <> 'Draft'
+		)
+		SELECT ` + group.key + `, ` + group.label + `, COUNT(*),
+			SUM(p.work_orders), SUM(p.labor_hours), SUM(p.labor_cost), SUM(p.parts_cost), SUM(p.downtime_hours),
+			COUNT(*) OVER ()
+		FROM per_asset p
+		` + group.join + `
+		WHERE p.work_orders > 0 OR p.labor_hours > 0 OR p.parts_cost > 0 OR p.downtime_hours > 0
+		GROUP BY 1, 2
+		ORDER BY ` + sort + ` DESC, 2
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, params.TenantID, params.From, params.To, params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	actors := []model.BadActor{}
+	total := 0
+	for rows.Next() {
+		var b model.BadActor
+		if err := rows.Scan(&b.Key, &b.Label, &b.Assets, &b.WorkOrders,
+			&b.LaborHours, &b.LaborCost, &b.PartsCost, &b.DowntimeHours, &total); err != nil {
+			return nil, 0, err
+		}
+		b.Rank = len(actors) + 1
+		b.TotalCost = b.LaborCost + b.PartsCost
+		actors = append(actors, b)
+	}
+
+	return actors, total, rows.Err()
+}
+
+// CaptureSnapshots writes the daily KPI snapshot for every tenant and day in
+// [from, to] (UTC dates) that doesn't have one yet; existing snapshots are never
+// recomputed. Returns the number of snapshots written.
+func (r *AnalyticsRepository) CaptureSnapshots(ctx context.Context, from, to time.Time) (int, error) {
+	const dayEnd = `(d.day + INTERVAL '1 day')`
+	// Preventive work order w is due at its SLA resolution deadline, else after the grace period
+	const pmDue = `COALESCE(w.resolve_by, w.created_at + make_interval(days => $3))`
+
+	query := `
+		INSERT INTO analytics_snapshots (
+			tenant_id, snapshot_date, wo_opened, wo_closed, wo_backlog,
+			pm_due, pm_on_time, labor_cost, parts_cost, downtime_hours
+		)
+		SELECT t.id, d.day::date,
+			(SELECT COUNT(*) FROM work_orders w
+				WHERE w.tenant_id = t.id AND w.created_at >= d.day AND w.created_at < ` + dayEnd + `),
+			(SELECT COUNT(*) FROM work_orders w
+				WHERE w.tenant_id = t.id AND w.status IN ('Work_Complete', 'Closed')
+					AND w.completed_at >= d.day AND w.completed_at < ` + dayEnd + `),
+			(SELECT COUNT(*) FROM work_orders w
+				WHERE w.tenant_id = t.id AND w.status <> 'Cancelled' AND w.created_at < ` + dayEnd + `
+					AND (w.completed_at IS NULL OR w.completed_at >= ` + dayEnd + `)),
+			(SELECT COUNT(*) FROM work_orders w
+				WHERE w.tenant_id = t.id AND w.origin = 'Preventive_Auto' AND w.status <> 'Cancelled'
+					AND ` + pmDue + ` >= d.day AND ` + pmDue + ` < ` + dayEnd + `),
+			(SELECT COUNT(*) FROM work_orders w
+				WHERE w.tenant_id = t.id AND w.origin = 'Preventive_Auto' AND w.status <> 'Cancelled'
+					AND ` + pmDue + ` >= d.day AND ` + pmDue + ` < ` + dayEnd + `
+					AND w.completed_at <= ` + pmDue + `),
+			(SELECT COALESCE(SUM(l.hours_spent * ` + laborRateExpr + `), 0)
+				FROM wo_labor_logs l
+				JOIN work_orders w ON w.id = l.work_order_id
+				JOIN users u ON u.id = l.user_id
+				WHERE w.tenant_id = t.id AND l.date_performed = d.day::date),
+			(SELECT COALESCE(SUM(rl.quantity * COALESCE(rl.unit_cost, p.unit_cost, 0)), 0)
+				FROM wo_resource_logs rl
+				JOIN work_orders w ON w.id = rl.work_order_id
+				LEFT JOIN parts p ON p.id = rl.part_id
+				WHERE w.tenant_id = t.id AND rl.created_at >= d.day AND rl.created_at < ` + dayEnd + `),
+			(SELECT COALESCE(SUM(GREATEST(0, EXTRACT(EPOCH FROM (
+					LEAST(COALESCE(dt.ended_at, NOW()::timestamp), ` + dayEnd + `) - GREATEST(dt.started_at, d.day)
+				)) / 3600)), 0)
+				FROM asset_downtime dt
+				WHERE dt.tenant_id = t.id AND dt.started_at < ` + dayEnd + `
+					AND COALESCE(dt.ended_at, NOW()::timestamp) > d.day)
+		FROM tenants t
+		CROSS JOIN generate_series($1::timestamp, $2::timestamp, INTERVAL '1 day') AS d(day)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM analytics_snapshots s WHERE s.tenant_id = t.id AND s.snapshot_date = d.day::date
+		)
+		ON CONFLICT (tenant_id, snapshot_date) DO NOTHING
+	`
+
+	result, err := r.db.Exec(ctx, query, from, to, model.PMComplianceGraceDays)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// GetTrends aggregates the tenant's daily snapshots in [from, to) into buckets of the
+// given interval (a TrendInterval constant), including empty buckets
+func (r *AnalyticsRepository) GetTrends(ctx context.Context, tenantID, interval string, from, to time.Time) ([]model.TrendBucket, error) {
+	query := `
+		WITH buckets AS (
+			SELECT generate_series(
+				date_trunc($4, $2::timestamp), $3::timestamp - INTERVAL '1 day', ('1 ' || $4)::interval
+			) AS start
+		)
+		SELECT b.start, COUNT(s.snapshot_date),
+			COALESCE(SUM(s.wo_opened), 0), COALESCE(SUM(s.wo_closed), 0),
+			(array_agg(s.wo_backlog ORDER BY s.snapshot_date DESC NULLS LAST))[1],
+			COALESCE(SUM(s.pm_due), 0), COALESCE(SUM(s.pm_on_time), 0),
+			COALESCE(SUM(s.labor_cost), 0)::float8, COALESCE(SUM(s.parts_cost), 0)::float8,
+			COALESCE(SUM(s.downtime_hours), 0)::float8
+		FROM buckets b
+		LEFT JOIN analytics_snapshots s
+			ON s.tenant_id = $1
+			AND date_trunc($4, s.snapshot_date::timestamp) = b.start
+			AND s.snapshot_date >= $2::timestamp::date AND s.snapshot_date < $3::timestamp::date
+		GROUP BY b.start
+		ORDER BY b.start
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []model.TrendBucket{}
+	for rows.Next() {
+		var b model.TrendBucket
+		var start time.Time
+		if err := rows.Scan(&start, &b.Days, &b.Opened, &b.Closed, &b.Backlog,
+			&b.PMDue, &b.PMOnTime, &b.LaborCost, &b.PartsCost, &b.DowntimeHours); err != nil {
+			return nil, err
+		}
+		b.Start = start.Format("2006-01-02")
+		b.Spend = b.LaborCost + b.PartsCost
+		if b.PMDue > 0 {
+			pct := math.Round(float64(b.PMOnTime)*1000/float64(b.PMDue)) / 10
+			b.PMCompliancePct = &pct
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"ioi-amms/internal/model"
 
@@ -13,9 +14,41 @@ import (
 )
 
 var (
-	ErrAssetNotFound = errors.New("asset not found")
+	ErrAssetNotFound       = errors.New("asset not found")
+	ErrAssetStatusConflict = errors.New("asset status changed concurrently")
+	ErrAssetParentInvalid  = errors.New("parent asset not found in tenant")
+	ErrAssetHierarchyCycle = errors.New("asset cannot be placed below itself")
+	ErrAssetInTransit      = errors.New("asset or one of its descendants has an open transfer")
+	ErrAssetOpenWorkOrders = errors.New("asset has open work orders")
 )
 
+// assetColumns and assetJoins select an asset with its location and org unit names,
+// in the order read by scanAsset
+const (
+	assetColumns = `
+			a.id, a.tenant_id, a.parent_id, a.location_id, a.org_unit_id, a.name,
+			a.status, a.is_field_related, a.is_field_verified,
+			a.manufacturer, a.model_number, a.specs,
+			a.created_at, a.updated_at,
+			COALESCE(l.name, '') as location_name,
+			COALESCE(o.name, '') as org_unit_name`
+	assetJoins = `
+		LEFT JOIN locations l ON a.location_id = l.id
+		LEFT JOIN org_units o ON a.org_unit_id = o.id`
+)
+
+// scanAsset reads assetColumns, then any extra columns, into asset
+func scanAsset(row pgx.Row, asset *model.Asset, extra ...interface{}) error {
+	dest := []interface{}{
+		&asset.ID, &asset.TenantID, &asset.ParentID, &asset.LocationID, &asset.OrgUnitID, &asset.Name,
+		&asset.Status, &asset.IsFieldRelated, &asset.IsFieldVerified,
+		&asset.Manufacturer, &asset.ModelNumber, &asset.Specs,
+		&asset.CreatedAt, &asset.UpdatedAt,
+		&asset.LocationName, &asset.OrgUnitName,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
 // AssetRepository handles asset data access
 type AssetRepository struct {
 	db *pgxpool.Pool
@@ -183,14 +216,89 @@ func (r *AssetRepository) Create(ctx context.Context, asset *model.Asset) error
 		RETURNING id, created_at, updated_at
 	`
 
-	return r.db.QueryRow(ctx, query,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if asset.ParentID != nil {
+		if err := checkAssetParent(ctx, tx, asset.TenantID, "", *asset.ParentID); err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRow(ctx, query,
 		asset.TenantID, asset.ParentID, asset.LocationID, asset.OrgUnitID, asset.Name, asset.Status,
 		asset.IsFieldRelated, asset.IsFieldVerified, asset.Manufacturer, asset.ModelNumber, asset.Specs,
 	).Scan(&asset.ID, &asset.CreatedAt, &asset.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	// Assets registered already out of service start a downtime event
+	if model.IsDownStatus(asset.Status) {
+		if err := syncDowntime(ctx, tx, asset.TenantID, asset.ID, asset.Status, nil); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
 }
 
-// Update modifies an existing asset (v1.1 schema)
-func (r *AssetRepository) Update(ctx context.Context, asset *model.Asset) error {
+// Update modifies an existing asset (v1.1 schema) that was read with status from;
+// if its status has changed since, ErrAssetStatusConflict is returned. A new parent
+// must be in the same tenant and not below the asset. A new location moves the
+// asset's descendants with it, recorded as a relocation by userID. A status change
+// opens or closes the asset's downtime event. All in the same transaction.
+func (r *AssetRepository) Update(ctx context.Context, asset *model.Asset, from, userID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var previous string
+	var previousParent, previousLocation *string
+	err = tx.QueryRow(ctx, `SELECT status, parent_id, location_id FROM assets WHERE id = $1 FOR UPDATE`, asset.ID).
+		Scan(&previous, &previousParent, &previousLocation)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAssetNotFound
+		}
+		return err
+	}
+	if previous != from {
+		return ErrAssetStatusConflict
+	}
+
+	// Checked under the row lock, so a work order raised meanwhile is not left on an archived asset
+	if asset.Status == model.AssetStatusArchived && previous != model.AssetStatusArchived {
+		var open bool
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM work_orders WHERE asset_id = $1 AND status NOT IN ('Closed', 'Cancelled'))
+		`, asset.ID).Scan(&open)
+		if err != nil {
+			return err
+		}
+		if open {
+			return ErrAssetOpenWorkOrders
+		}
+	}
+
+	if asset.ParentID != nil && (previousParent == nil || *previousParent != *asset.ParentID) {
+		if err := checkAssetParent(ctx, tx, asset.TenantID, asset.ID, *asset.ParentID); err != nil {
+			return err
+		}
+	}
+
+	if asset.LocationID != nil && (previousLocation == nil || *previousLocation != *asset.LocationID) {
+		ref := model.NewShipmentRef(time.Now())
+		if _, err := relocateSubtree(ctx, tx, asset.TenantID, asset.ID, *asset.LocationID, ref, userID); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE assets
 		SET parent_id = $2, location_id = $3, org_unit_id = $4, name = $5, status = $6,
@@ -200,10 +308,21 @@ func (r *AssetRepository) Update(ctx context.Context, asset *model.Asset) error
 		RETURNING updated_at
 	`
 
-	return r.db.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		asset.ID, asset.ParentID, asset.LocationID, asset.OrgUnitID, asset.Name, asset.Status,
 		asset.IsFieldRelated, asset.IsFieldVerified, asset.Manufacturer, asset.ModelNumber, asset.Specs,
 	).Scan(&asset.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if previous != asset.Status && (model.IsDownStatus(previous) || model.IsDownStatus(asset.Status)) {
+		if err := syncDowntime(ctx, tx, asset.TenantID, asset.ID, asset.Status, nil); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
 }
 
 // Delete removes an asset
@@ -218,3 +337,285 @@ func (r *AssetRepository) Delete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// checkAssetParent verifies, inside tx, that parentID is an asset of the tenant and
+// (for an existing asset) neither the asset itself nor one of its descendants.
+// Re-parenting is serialized per tenant so concurrent moves cannot form a cycle.
+func checkAssetParent(ctx context.Context, tx pgx.Tx, tenantID, assetID, parentID string) error {
+	var parentTenant string
+	err := tx.QueryRow(ctx, `SELECT tenant_id FROM assets WHERE id = $1`, parentID).Scan(&parentTenant)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAssetParentInvalid
+		}
+		return err
+	}
+	if parentTenant != tenantID {
+		return ErrAssetParentInvalid
+	}
+	if assetID == "" {
+		return nil
+	}
+
+	if err := lockAssetHierarchy(ctx, tx, tenantID); err != nil {
+		return err
+	}
+
+	// Walk up from the new parent; reaching the asset would close a loop
+	query := `
+		WITH RECURSIVE up AS (
+			SELECT id, parent_id, ARRAY[id] AS path FROM assets WHERE id = $1
+			UNION ALL
+			SELECT a.id, a.parent_id, up.path || a.id
+			FROM assets a JOIN up ON a.id = up.parent_id
+			WHERE NOT a.id = ANY(up.path)
+		)
+		SELECT EXISTS (SELECT 1 FROM up WHERE id = $2)
+	`
+
+	var cycle bool
+	if err := tx.QueryRow(ctx, query, parentID, assetID).Scan(&cycle); err != nil {
+		return err
+	}
+	if cycle {
+		return ErrAssetHierarchyCycle
+	}
+	return nil
+}
+
+// lockAssetHierarchy serializes, inside tx, changes to the tenant's asset tree:
+// re-parenting and moves of whole subtrees
+func lockAssetHierarchy(ctx context.Context, tx pgx.Tx, tenantID string) error {
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('asset_hierarchy:' || $1))`, tenantID)
+	return err
+}
+
+// assetSubtreeCTE selects the ids of asset $1 of tenant $2 and all its descendants
+const assetSubtreeCTE = `
+		WITH RECURSIVE subtree AS (
+			SELECT id, ARRAY[id] AS path FROM assets WHERE id = $1 AND tenant_id = $2
+			UNION ALL
+			SELECT c.id, s.path || c.id
+			FROM assets c JOIN subtree s ON c.parent_id = s.id
+			WHERE NOT c.id = ANY(s.path)
+		)`
+
+// relocateSubtree moves an asset and all its descendants to location toID inside
+// tx, recording a received Relocation movement under ref for each asset that
+// changes location. The destination must be a location of the tenant, and no
+// asset in the subtree may have an open transfer. It returns the ids of the moved assets.
+func relocateSubtree(ctx context.Context, tx pgx.Tx, tenantID, rootID, toID, ref, userID string) ([]string, error) {
+	var ok bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM locations WHERE id = $1 AND tenant_id = $2)`, toID, tenantID).Scan(&ok)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLocationNotFound
+	}
+
+	if err := lockAssetHierarchy(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	var inTransit bool
+	err = tx.QueryRow(ctx, assetSubtreeCTE+`
+		SELECT EXISTS (
+			SELECT 1 FROM asset_movements m JOIN subtree s ON m.asset_id = s.id
+			WHERE m.status = ANY($3)
+		)
+	`, rootID, tenantID, model.OpenMovementStatuses).Scan(&inTransit)
+	if err != nil {
+		return nil, err
+	}
+	if inTransit {
+		return nil, ErrAssetInTransit
+	}
+
+	query := assetSubtreeCTE + `,
+		moved AS (
+			SELECT a.id, a.location_id
+			FROM assets a JOIN subtree s ON s.id = a.id
+			WHERE a.location_id IS DISTINCT FROM $3
+			FOR UPDATE OF a
+		),
+		logged AS (
+			INSERT INTO asset_movements (tenant_id, asset_id, from_location_id, to_location_id, status,
+				transfer_type, shipment_ref, created_by_user_id, received_by_user_id, received_at)
+			SELECT $2, id, location_id, $3, 'Received', 'Relocation', $4, $5::uuid, $5::uuid, NOW()
+			FROM moved
+		)
+		UPDATE assets a SET location_id = $3, updated_at = NOW()
+		FROM moved
+		WHERE a.id = moved.id
+		RETURNING a.id
+	`
+
+	rows, err := tx.Query(ctx, query, rootID, tenantID, toID, ref, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	moved := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		moved = append(moved, id)
+	}
+	return moved, rows.Err()
+}
+
+// Move relocates an asset of the tenant and all its descendants to a location in
+// one transaction, recording a movement per moved asset under a shared shipment
+// reference. It returns the reference and the moved assets, by name.
+func (r *AssetRepository) Move(ctx context.Context, tenantID, id, toLocationID, userID string) (string, []model.Asset, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM assets WHERE id = $1 AND tenant_id = $2)`, id, tenantID).Scan(&exists)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exists {
+		return "", nil, ErrAssetNotFound
+	}
+
+	ref := model.NewShipmentRef(time.Now())
+	moved, err := relocateSubtree(ctx, tx, tenantID, id, toLocationID, ref, userID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT `+assetColumns+`
+		FROM assets a`+assetJoins+`
+		WHERE a.id = ANY($1)
+		ORDER BY a.name, a.id
+	`, moved)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rows.Close()
+
+	assets := []model.Asset{}
+	for rows.Next() {
+		var asset model.Asset
+		if err := scanAsset(rows, &asset); err != nil {
+			return "", nil, err
+		}
+		assets = append(assets, asset)
+	}
+	if err := rows.Err(); err != nil {
+		return "", nil, err
+	}
+	rows.Close()
+
+	return ref, assets, tx.Commit(ctx)
+}
+
+// ListDescendants retrieves the assets below an asset of the tenant, at most
+// maxDepth levels down (0 for all), depth-first with siblings by name. The asset
+// itself is not included.
+func (r *AssetRepository) ListDescendants(ctx context.Context, tenantID, id string, maxDepth int) ([]model.Asset, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth, ARRAY[id] AS path, ARRAY[name || ' ' || id::text] AS sort_path
+			FROM assets WHERE id = $1 AND tenant_id = $2
+			UNION ALL
+			SELECT c.id, s.depth + 1, s.path || c.id, s.sort_path || (c.name || ' ' || c.id::text)
+			FROM assets c JOIN subtree s ON c.parent_id = s.id
+			WHERE NOT c.id = ANY(s.path) AND ($3 = 0 OR s.depth < $3)
+		)
+		SELECT ` + assetColumns + `, s.depth
+		FROM subtree s
+		JOIN assets a ON a.id = s.id` + assetJoins + `
+		ORDER BY s.sort_path
+	`
+
+	return r.listHierarchy(ctx, query, id, tenantID, maxDepth)
+}
+
+// ListAncestors retrieves the chain of parents above an asset of the tenant, from
+// the root down to its direct parent. The asset itself is not included.
+func (r *AssetRepository) ListAncestors(ctx context.Context, tenantID, id string) ([]model.Asset, error) {
+	query := `
+		WITH RECURSIVE up AS (
+			SELECT id, parent_id, 0 AS depth, ARRAY[id] AS path
+			FROM assets WHERE id = $1 AND tenant_id = $2
+			UNION ALL
+			SELECT p.id, p.parent_id, up.depth + 1, up.path || p.id
+			FROM assets p JOIN up ON p.id = up.parent_id
+			WHERE NOT p.id = ANY(up.path)
+		)
+		SELECT ` + assetColumns + `, up.depth
+		FROM up
+		JOIN assets a ON a.id = up.id` + assetJoins + `
+		ORDER BY up.depth DESC
+	`
+
+	return r.listHierarchy(ctx, query, id, tenantID)
+}
+
+// listHierarchy runs a hierarchy query whose rows include the asset asked about at
+// depth 0, returning ErrAssetNotFound when it is missing and the other rows otherwise
+func (r *AssetRepository) listHierarchy(ctx context.Context, query string, args ...interface{}) ([]model.Asset, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := false
+	assets := []model.Asset{}
+	for rows.Next() {
+		var asset model.Asset
+		if err := scanAsset(rows, &asset, &asset.Depth); err != nil {
+			return nil, err
+		}
+		if asset.Depth == 0 {
+			found = true
+			continue
+		}
+		assets = append(assets, asset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrAssetNotFound
+	}
+	return assets, nil
+}
+
+// ListAll retrieves every asset of the tenant, by name, for building the full tree
+func (r *AssetRepository) ListAll(ctx context.Context, tenantID string) ([]model.Asset, error) {
+	query := `
+		SELECT ` + assetColumns + `
+		FROM assets a` + assetJoins + `
+		WHERE a.tenant_id = $1
+		ORDER BY a.name, a.id
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assets := []model.Asset{}
+	for rows.Next() {
+		var asset model.Asset
+		if err := scanAsset(rows, &asset); err != nil {
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+	return assets, rows.Err()
+}
@@ -8,6 +8,7 @@ import (
 
 	"ioi-amms/internal/model"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -23,9 +24,20 @@ func NewAuditRepository(db *pgxpool.Pool) *AuditRepository {
 
 // Create inserts a new audit log
 func (r *AuditRepository) Create(ctx context.Context, log *model.AuditLog) error {
+	return insertAuditLog(ctx, r.db, log)
+}
+
+// rowQuerier is satisfied by both the pool and a transaction
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// insertAuditLog writes an audit log through q, so repositories can record the
+// audit entry in the same transaction as the change it describes
+func insertAuditLog(ctx context.Context, q rowQuerier, log *model.AuditLog) error {
 	query := `
-		INSERT INTO audit_logs (user_id, action, entity_type, entity_id, changes)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO audit_logs (tenant_id, user_id, action, entity_type, entity_id, changes)
+		VALUES (COALESCE($1, (SELECT tenant_id FROM users WHERE id = $2)), $2, $3, $4, $5, $6)
 		RETURNING id, created_at
 	`
 
@@ -34,8 +46,9 @@ func (r *AuditRepository) Create(ctx context.Context, log *model.AuditLog) error
 		return err
 	}
 
-	return r.db.QueryRow(ctx, query,
-		log.UserID, log.Action, log.EntityType, log.EntityID, changesJSON,
+	// System events (e.g. the PM evaluator) have no acting user
+	return q.QueryRow(ctx, query,
+		nullString(log.TenantID), nullString(log.UserID), log.Action, log.EntityType, log.EntityID, changesJSON,
 	).Scan(&log.ID, &log.CreatedAt)
 }
 
@@ -49,13 +62,14 @@ func (r *AuditRepository) List(ctx context.Context, params model.AuditListParams
 	// Or explicitly passed TenantID (if we join users)
 	// The DB schema: audit_logs(user_id) -> users(id, tenant_id)
 
-	// Join users to get email/name and filter by tenant
+	// Join users to get email/name; system events have no user, so filter on the
+	// log's own tenant_id and fall back to the user's tenant for older rows
 	baseQuery := `
 		FROM audit_logs a
-		JOIN users u ON a.user_id = u.id
+		LEFT JOIN users u ON a.user_id = u.id
 	`
 
-	conditions = append(conditions, fmt.Sprintf("u.tenant_id = $%d", argNum))
+	conditions = append(conditions, fmt.Sprintf("COALESCE(a.tenant_id, u.tenant_id) = $%d", argNum))
 	args = append(args, params.TenantID)
 	argNum++
 
@@ -125,8 +139,8 @@ func (r *AuditRepository) List(ctx context.Context, params model.AuditListParams
 
 	query := fmt.Sprintf(`
 		SELECT 
-			a.id, a.user_id, a.action, a.entity_type, a.entity_id, a.changes, a.created_at,
-			COALESCE(u.full_name, u.email) as user_name
+			a.id, COALESCE(a.user_id::text, ''), a.action, a.entity_type, a.entity_id, a.changes, a.created_at,
+			COALESCE(u.full_name, u.email, 'System') as user_name
 		%s
 		WHERE %s
 		ORDER BY a.created_at DESC
@@ -170,3 +184,11 @@ func (r *AuditRepository) List(ctx context.Context, params model.AuditListParams
 		TotalPages: totalPages,
 	}, nil
 }
+
+// nullString converts an empty string to a SQL NULL
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
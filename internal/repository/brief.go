@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BriefRepository handles Monday Morning Brief subscriptions, deliveries and activity
+type BriefRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewBriefRepository creates a new brief repository
+func NewBriefRepository(db *pgxpool.Pool) *BriefRepository {
+	return &BriefRepository{db: db}
+}
+
+// IsSubscribed reports whether the user receives the weekly brief
+func (r *BriefRepository) IsSubscribed(ctx context.Context, userID string) (bool, error) {
+	var subscribed bool
+	err := r.db.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM brief_subscriptions WHERE user_id = $1)", userID,
+	).Scan(&subscribed)
+	return subscribed, err
+}
+
+// Subscribe signs the user up for the weekly brief; subscribing twice is a no-op
+func (r *BriefRepository) Subscribe(ctx context.Context, tenantID, userID string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO brief_subscriptions (user_id, tenant_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID, tenantID)
+	return err
+}
+
+// Unsubscribe stops the weekly brief for the user
+func (r *BriefRepository) Unsubscribe(ctx context.Context, userID string) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM brief_subscriptions WHERE user_id = $1", userID)
+	return err
+}
+
+// ListPending retrieves active subscribers who have not yet been sent the brief for
+// the week starting weekStart, grouped by tenant
+func (r *BriefRepository) ListPending(ctx context.Context, weekStart time.Time) ([]model.BriefSubscriber, error) {
+	query := `
+		SELECT u.id, u.tenant_id, u.email, COALESCE(u.full_name, u.email), u.role
+		FROM brief_subscriptions s
+		JOIN users u ON u.id = s.user_id
+		WHERE u.is_active = TRUE
+			AND NOT EXISTS (
+				SELECT 1 FROM brief_deliveries d
+				WHERE d.user_id = u.id AND d.week_start = $1::timestamp::date
+			)
+		ORDER BY u.tenant_id, u.email
+	`
+
+	rows, err := r.db.Query(ctx, query, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscribers []model.BriefSubscriber
+	for rows.Next() {
+		var s model.BriefSubscriber
+		if err := rows.Scan(&s.UserID, &s.TenantID, &s.Email, &s.FullName, &s.Role); err != nil {
+			return nil, err
+		}
+		subscribers = append(subscribers, s)
+	}
+
+	return subscribers, rows.Err()
+}
+
+// RecordDelivery marks the week's brief as sent to the user
+func (r *BriefRepository) RecordDelivery(ctx context.Context, tenantID, userID string, weekStart time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO brief_deliveries (tenant_id, user_id, week_start)
+		VALUES ($1, $2, $3::timestamp::date)
+		ON CONFLICT (user_id, week_start) DO NOTHING
+	`, tenantID, userID, weekStart)
+	return err
+}
+
+// WorkOrderActivity counts the tenant's work orders opened and completed (reached
+// Work_Complete, whether or not since Closed) in [from, to), and lists up to limit
+// of the completed ones, latest first
+func (r *BriefRepository) WorkOrderActivity(ctx context.Context, tenantID string, from, to time.Time, limit int) (int, int, []model.WorkOrderLink, error) {
+	var opened, completed int
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE created_at >= $2 AND created_at < $3),
+			COUNT(*) FILTER (WHERE completed_at >= $2 AND completed_at < $3 AND status IN ($4, $5))
+		FROM work_orders
+		WHERE tenant_id = $1
+	`, tenantID, from, to, model.WOStatusWorkComplete, model.WOStatusClosed).Scan(&opened, &completed)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, readable_id, source_task_id, status, priority, title
+		FROM work_orders
+		WHERE tenant_id = $1 AND completed_at >= $2 AND completed_at < $3 AND status IN ($4, $5)
+		ORDER BY completed_at DESC
+		LIMIT $6
+	`, tenantID, from, to, model.WOStatusWorkComplete, model.WOStatusClosed, limit)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	defer rows.Close()
+
+	links := []model.WorkOrderLink{}
+	for rows.Next() {
+		var l model.WorkOrderLink
+		if err := rows.Scan(&l.ID, &l.ReadableID, &l.SourceTaskID, &l.Status, &l.Priority, &l.Title); err != nil {
+			return 0, 0, nil, err
+		}
+		links = append(links, l)
+	}
+
+	return opened, completed, links, rows.Err()
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrChecklistTemplateNotFound = errors.New("checklist template not found")
+	ErrChecklistTemplateInUse    = errors.New("checklist template is used by a PM schedule")
+)
+
+// ChecklistRepository handles checklist template data access
+type ChecklistRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewChecklistRepository creates a new checklist template repository
+func NewChecklistRepository(db *pgxpool.Pool) *ChecklistRepository {
+	return &ChecklistRepository{db: db}
+}
+
+const checklistTemplateColumns = `id, tenant_id, title, description, items, created_at`
+
+// scanChecklistTemplate scans a row selected with checklistTemplateColumns
+func scanChecklistTemplate(row pgx.Row, t *model.ChecklistTemplate) error {
+	var itemsJSON []byte
+	if err := row.Scan(&t.ID, &t.TenantID, &t.Title, &t.Description, &itemsJSON, &t.CreatedAt); err != nil {
+		return err
+	}
+
+	t.Items = []model.ChecklistItem{}
+	if len(itemsJSON) > 0 {
+		return json.Unmarshal(itemsJSON, &t.Items)
+	}
+	return nil
+}
+
+// FindByID retrieves a checklist template by ID
+func (r *ChecklistRepository) FindByID(ctx context.Context, id string) (*model.ChecklistTemplate, error) {
+	query := `SELECT ` + checklistTemplateColumns + ` FROM checklist_templates WHERE id = $1`
+
+	var t model.ChecklistTemplate
+	if err := scanChecklistTemplate(r.db.QueryRow(ctx, query, id), &t); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrChecklistTemplateNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// List retrieves all checklist templates for a tenant
+func (r *ChecklistRepository) List(ctx context.Context, tenantID string) ([]model.ChecklistTemplate, error) {
+	query := `SELECT ` + checklistTemplateColumns + ` FROM checklist_templates WHERE tenant_id = $1 ORDER BY title`
+
+	rows, err := r.db.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []model.ChecklistTemplate{}
+	for rows.Next() {
+		var t model.ChecklistTemplate
+		if err := scanChecklistTemplate(rows, &t); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, rows.Err()
+}
+
+// Create inserts a new checklist template
+func (r *ChecklistRepository) Create(ctx context.Context, t *model.ChecklistTemplate) error {
+	itemsJSON, err := json.Marshal(t.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO checklist_templates (tenant_id, title, description, items)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query, t.TenantID, t.Title, t.Description, itemsJSON).Scan(&t.ID, &t.CreatedAt)
+}
+
+// Update modifies an existing checklist template. Tasks already spawned onto
+// work orders are copies and are not affected.
+func (r *ChecklistRepository) Update(ctx context.Context, t *model.ChecklistTemplate) error {
+	itemsJSON, err := json.Marshal(t.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE checklist_templates
+		SET title = $2, description = $3, items = $4
+		WHERE id = $1
+	`
+
+	_, err = r.db.Exec(ctx, query, t.ID, t.Title, t.Description, itemsJSON)
+	return err
+}
+
+// Delete removes a checklist template. Templates still referenced by a PM schedule cannot be deleted.
+func (r *ChecklistRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM checklist_templates WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrChecklistTemplateInUse
+		}
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrChecklistTemplateNotFound
+	}
+	return nil
+}
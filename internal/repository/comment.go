@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+)
+
+// CommentRepository handles work order comment data access
+type CommentRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCommentRepository creates a new comment repository
+func NewCommentRepository(db *pgxpool.Pool) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+const commentSelect = `
+	SELECT c.id, c.work_order_id, c.parent_comment_id, c.user_id, c.body,
+		ARRAY(SELECT m.user_id::text FROM wo_comment_mentions m WHERE m.comment_id = c.id),
+		c.created_at, c.edited_at, COALESCE(u.full_name, u.email), w.title
+	FROM wo_comments c
+	JOIN users u ON u.id = c.user_id
+	JOIN work_orders w ON w.id = c.work_order_id
+`
+
+// scanComment scans a row selected with commentSelect
+func scanComment(row pgx.Row, c *model.WOComment) error {
+	return row.Scan(&c.ID, &c.WorkOrderID, &c.ParentID, &c.UserID, &c.Body,
+		&c.Mentions, &c.CreatedAt, &c.EditedAt, &c.UserName, &c.WorkOrderTitle)
+}
+
+// queryComments runs a commentSelect query and collects the rows
+func (r *CommentRepository) queryComments(ctx context.Context, query string, args ...interface{}) ([]model.WOComment, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.WOComment{}
+	for rows.Next() {
+		var c model.WOComment
+		if err := scanComment(rows, &c); err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+
+	return comments, rows.Err()
+}
+
+// FindByID retrieves a comment by ID
+func (r *CommentRepository) FindByID(ctx context.Context, id string) (*model.WOComment, error) {
+	var c model.WOComment
+	if err := scanComment(r.db.QueryRow(ctx, commentSelect+" WHERE c.id = $1", id), &c); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListByWorkOrder retrieves a work order's comments, oldest first
+func (r *CommentRepository) ListByWorkOrder(ctx context.Context, workOrderID string) ([]model.WOComment, error) {
+	return r.queryComments(ctx, commentSelect+" WHERE c.work_order_id = $1 ORDER BY c.created_at", workOrderID)
+}
+
+// ListMentions retrieves the latest comments mentioning a user, newest first
+func (r *CommentRepository) ListMentions(ctx context.Context, userID string, limit int) ([]model.WOComment, error) {
+	query := commentSelect + `
+		WHERE EXISTS (SELECT 1 FROM wo_comment_mentions m WHERE m.comment_id = c.id AND m.user_id = $1)
+		ORDER BY c.created_at DESC
+		LIMIT $2
+	`
+	return r.queryComments(ctx, query, userID, limit)
+}
+
+// Create inserts a comment and its mentions in one transaction
+func (r *CommentRepository) Create(ctx context.Context, c *model.WOComment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO wo_comments (work_order_id, parent_comment_id, user_id, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(ctx, query, c.WorkOrderID, c.ParentID, c.UserID, c.Body).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertMentions(ctx, tx, c.ID, c.Mentions); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Update replaces a comment's body and mentions and stamps edited_at
+func (r *CommentRepository) Update(ctx context.Context, c *model.WOComment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `UPDATE wo_comments SET body = $2, edited_at = NOW() WHERE id = $1 RETURNING edited_at`,
+		c.ID, c.Body).Scan(&c.EditedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM wo_comment_mentions WHERE comment_id = $1`, c.ID); err != nil {
+		return err
+	}
+	if err := insertMentions(ctx, tx, c.ID, c.Mentions); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// insertMentions records the users mentioned in a comment
+func insertMentions(ctx context.Context, tx pgx.Tx, commentID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO wo_comment_mentions (comment_id, user_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`, commentID, userIDs)
+	return err
+}
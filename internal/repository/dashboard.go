@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DashboardRepository handles stored dashboard layouts
+type DashboardRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDashboardRepository creates a new dashboard layout repository
+func NewDashboardRepository(db *pgxpool.Pool) *DashboardRepository {
+	return &DashboardRepository{db: db}
+}
+
+// FindForUser retrieves the user's own layout, or nil if they have none
+func (r *DashboardRepository) FindForUser(ctx context.Context, userID string) (*model.DashboardLayout, error) {
+	return r.find(ctx, model.DashboardSourceUser,
+		"SELECT widgets, updated_at FROM dashboard_layouts WHERE user_id = $1", userID)
+}
+
+// FindForRole retrieves the tenant's default layout for a role, or nil if it has none
+func (r *DashboardRepository) FindForRole(ctx context.Context, tenantID, role string) (*model.DashboardLayout, error) {
+	layout, err := r.find(ctx, model.DashboardSourceRole,
+		"SELECT widgets, updated_at FROM dashboard_layouts WHERE tenant_id = $1 AND role = $2 AND user_id IS NULL",
+		tenantID, role)
+	if layout != nil {
+		layout.Role = role
+	}
+	return layout, err
+}
+
+func (r *DashboardRepository) find(ctx context.Context, source, query string, args ...interface{}) (*model.DashboardLayout, error) {
+	var widgetsJSON []byte
+	var updatedAt time.Time
+	err := r.db.QueryRow(ctx, query, args...).Scan(&widgetsJSON, &updatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	layout := &model.DashboardLayout{Source: source, Widgets: []model.DashboardPlacement{}, UpdatedAt: &updatedAt}
+	if err := json.Unmarshal(widgetsJSON, &layout.Widgets); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// SaveForUser creates or replaces the user's own layout
+func (r *DashboardRepository) SaveForUser(ctx context.Context, tenantID, userID string, widgets []model.DashboardPlacement) (time.Time, error) {
+	widgetsJSON, err := json.Marshal(widgets)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	query := `
+		INSERT INTO dashboard_layouts (tenant_id, user_id, widgets, updated_by_user_id)
+		VALUES ($1, $2, $3, $2)
+		ON CONFLICT (user_id) WHERE user_id IS NOT NULL
+		DO UPDATE SET widgets = EXCLUDED.widgets, updated_by_user_id = EXCLUDED.updated_by_user_id, updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	var updatedAt time.Time
+	err = r.db.QueryRow(ctx, query, tenantID, userID, widgetsJSON).Scan(&updatedAt)
+	return updatedAt, err
+}
+
+// SaveForRole creates or replaces the tenant's default layout for a role
+func (r *DashboardRepository) SaveForRole(ctx context.Context, tenantID, role, updatedBy string, widgets []model.DashboardPlacement) (time.Time, error) {
+	widgetsJSON, err := json.Marshal(widgets)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	query := `
+		INSERT INTO dashboard_layouts (tenant_id, role, widgets, updated_by_user_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, role) WHERE user_id IS NULL
+		DO UPDATE SET widgets = EXCLUDED.widgets, updated_by_user_id = EXCLUDED.updated_by_user_id, updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	var updatedAt time.Time
+	err = r.db.QueryRow(ctx, query, tenantID, role, widgetsJSON, updatedBy).Scan(&updatedAt)
+	return updatedAt, err
+}
+
+// DeleteForUser removes the user's own layout, reverting them to their role's
+func (r *DashboardRepository) DeleteForUser(ctx context.Context, userID string) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM dashboard_layouts WHERE user_id = $1", userID)
+	return err
+}
+
+// DeleteForRole removes the tenant's default layout for a role, reverting it to the built-in one
+func (r *DashboardRepository) DeleteForRole(ctx context.Context, tenantID, role string) error {
+	_, err := r.db.Exec(ctx,
+		"DELETE FROM dashboard_layouts WHERE tenant_id = $1 AND role = $2 AND user_id IS NULL", tenantID, role)
+	return err
+}
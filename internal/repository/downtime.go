@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// downtimeHoursExpr is the part of downtime event d inside the period [$2, $3),
+// in hours; open events run until now
+const downtimeHoursExpr = `GREATEST(0, EXTRACT(EPOCH FROM (
+	LEAST(COALESCE(d.ended_at, NOW()::timestamp), $3::timestamp, NOW()::timestamp) - GREATEST(d.started_at, $2::timestamp)
+)) / 3600)`
+
+// downtimeOverlapCond selects downtime events d overlapping the period [$2, $3)
+const downtimeOverlapCond = `d.started_at < $3::timestamp AND COALESCE(d.ended_at, NOW()::timestamp) > $2::timestamp`
+
+// DowntimeRepository handles asset downtime event data access
+type DowntimeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewDowntimeRepository creates a new downtime repository
+func NewDowntimeRepository(db *pgxpool.Pool) *DowntimeRepository {
+	return &DowntimeRepository{db: db}
+}
+
+// ListByAsset retrieves an asset's downtime events overlapping the period, oldest first
+func (r *DowntimeRepository) ListByAsset(ctx context.Context, assetID string, period model.Period) ([]model.DowntimeEvent, error) {
+	query := `
+		SELECT d.id, d.asset_id, d.status, d.work_order_id, d.started_at, d.ended_at, ` + downtimeHoursExpr + `
+		FROM asset_downtime d
+		WHERE d.asset_id = $1 AND ` + downtimeOverlapCond + `
+		ORDER BY d.started_at
+	`
+
+	rows, err := r.db.Query(ctx, query, assetID, period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.DowntimeEvent{}
+	for rows.Next() {
+		var e model.DowntimeEvent
+		if err := rows.Scan(&e.ID, &e.AssetID, &e.Status, &e.WorkOrderID, &e.StartedAt, &e.EndedAt, &e.Hours); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// Availability summarizes downtime per asset of a tenant over the period, most downtime
+// first. Draft and Archived assets are only listed if they were down in the period.
+func (r *DowntimeRepository) Availability(ctx context.Context, tenantID string, period model.Period, page, limit int) (*model.PaginatedResult[model.AssetAvailability], error) {
+	base := `
+		SELECT a.id, a.name, a.status, COALESCE(SUM(` + downtimeHoursExpr + `), 0) AS downtime_hours, COUNT(d.id)
+		FROM assets a
+		LEFT JOIN asset_downtime d ON d.asset_id = a.id AND ` + downtimeOverlapCond + `
+		WHERE a.tenant_id = $1
+		GROUP BY a.id, a.name, a.status
+		HAVING a.status NOT IN ('Draft', 'Archived') OR COUNT(d.id) > 0
+	`
+
+	var total int
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM ("+base+") x", tenantID, period.From, period.To).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	query := base + fmt.Sprintf(" ORDER BY downtime_hours DESC, a.name LIMIT %d OFFSET %d", limit, (page-1)*limit)
+	rows, err := r.db.Query(ctx, query, tenantID, period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	periodHours := period.Hours()
+	items := []model.AssetAvailability{}
+	for rows.Next() {
+		a := model.AssetAvailability{PeriodHours: periodHours}
+		if err := rows.Scan(&a.AssetID, &a.AssetName, &a.Status, &a.DowntimeHours, &a.Events); err != nil {
+			return nil, err
+		}
+		a.AvailabilityPct = model.Availability(periodHours, a.DowntimeHours)
+		items = append(items, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &model.PaginatedResult[model.AssetAvailability]{
+		Data:       items,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+// RestoreForWorkOrder returns an asset taken Down by the given work order to Active
+// and closes its downtime event. Red_Tag assets need a manual release. It reports
+// whether the asset was restored.
+func (r *DowntimeRepository) RestoreForWorkOrder(ctx context.Context, wo *model.WorkOrder) (bool, error) {
+	if wo.AssetID == nil {
+		return false, nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE assets SET status = 'Active', updated_at = NOW()
+		WHERE id = $1 AND status = 'Down' AND EXISTS (
+			SELECT 1 FROM asset_downtime d
+			WHERE d.asset_id = $1 AND d.ended_at IS NULL AND d.work_order_id = $2
+		)
+	`
+
+	result, err := tx.Exec(ctx, query, *wo.AssetID, wo.ID)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := syncDowntime(ctx, tx, wo.TenantID, *wo.AssetID, model.AssetStatusActive, nil); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// syncDowntime keeps the asset's downtime log in step with its new status, inside tx:
+// a down status opens an event (or updates the open one), any other status closes it.
+// workOrderID links the cause when opening.
+func syncDowntime(ctx context.Context, tx pgx.Tx, tenantID, assetID, status string, workOrderID *string) error {
+	if model.IsDownStatus(status) {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO asset_downtime (tenant_id, asset_id, status, work_order_id)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (asset_id) WHERE ended_at IS NULL
+			DO UPDATE SET status = EXCLUDED.status,
+				work_order_id = COALESCE(asset_downtime.work_order_id, EXCLUDED.work_order_id)
+		`, tenantID, assetID, status, workOrderID)
+		return err
+	}
+
+	_, err := tx.Exec(ctx, `UPDATE asset_downtime SET ended_at = NOW() WHERE asset_id = $1 AND ended_at IS NULL`, assetID)
+	return err
+}
+
+// takeAssetDown marks an Active asset Down for a Critical work order, inside tx, and
+// records the audit entry for userID, the user who made the work order Critical
+// (empty for system changes such as SLA escalation). An asset that is already down
+// gets the work order linked to its open downtime event instead.
+func takeAssetDown(ctx context.Context, tx pgx.Tx, wo *model.WorkOrder, userID string) error {
+	result, err := tx.Exec(ctx, `
+		UPDATE assets SET status = 'Down', updated_at = NOW()
+		WHERE id = $1 AND tenant_id = $2 AND status = 'Active'
+	`, *wo.AssetID, wo.TenantID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		_, err := tx.Exec(ctx, `
+			UPDATE asset_downtime SET work_order_id = $2
+			WHERE asset_id = $1 AND ended_at IS NULL AND work_order_id IS NULL
+		`, *wo.AssetID, wo.ID)
+		return err
+	}
+
+	if err := syncDowntime(ctx, tx, wo.TenantID, *wo.AssetID, model.AssetStatusDown, &wo.ID); err != nil {
+		return err
+	}
+
+	audit := &model.AuditLog{
+		TenantID:   wo.TenantID,
+		UserID:     userID,
+		Action:     model.AuditActionStatusChange,
+		EntityType: model.AuditEntityAsset,
+		EntityID:   *wo.AssetID,
+		Changes: map[string]interface{}{
+			"from":        model.AssetStatusActive,
+			"to":          model.AssetStatusDown,
+			"workOrderId": wo.ID,
+		},
+	}
+	return insertAuditLog(ctx, tx, audit)
+}
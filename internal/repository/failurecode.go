@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrFailureCodeNotFound = errors.New("failure code not found")
+	ErrFailureCodeExists   = errors.New("failure code already exists")
+	ErrFailureCodeInUse    = errors.New("failure code is in use")
+)
+
+// FailureCodeRepository handles failure code catalog data access
+type FailureCodeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewFailureCodeRepository creates a new failure code repository
+func NewFailureCodeRepository(db *pgxpool.Pool) *FailureCodeRepository {
+	return &FailureCodeRepository{db: db}
+}
+
+const failureCodeColumns = `id, tenant_id, parent_id, level, code, description, COALESCE(is_active, TRUE), created_at`
+
+func scanFailureCode(row pgx.Row, fc *model.FailureCode) error {
+	return row.Scan(&fc.ID, &fc.TenantID, &fc.ParentID, &fc.Level, &fc.Code, &fc.Description, &fc.IsActive, &fc.CreatedAt)
+}
+
+// FindByID retrieves a failure code by ID
+func (r *FailureCodeRepository) FindByID(ctx context.Context, id string) (*model.FailureCode, error) {
+	query := `SELECT ` + failureCodeColumns + ` FROM failure_codes WHERE id = $1`
+
+	var fc model.FailureCode
+	if err := scanFailureCode(r.db.QueryRow(ctx, query, id), &fc); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFailureCodeNotFound
+		}
+		return nil, err
+	}
+	return &fc, nil
+}
+
+// List retrieves a tenant's failure codes, optionally filtered by level and parent
+func (r *FailureCodeRepository) List(ctx context.Context, tenantID, level, parentID string, activeOnly bool) ([]model.FailureCode, error) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+	argNum := 2
+
+	if level != "" {
+		conditions = append(conditions, fmt.Sprintf("level = $%d", argNum))
+		args = append(args, level)
+		argNum++
+	}
+
+	if parentID != "" {
+		conditions = append(conditions, fmt.Sprintf("parent_id = $%d", argNum))
+		args = append(args, parentID)
+	}
+
+	if activeOnly {
+		conditions = append(conditions, "is_active = TRUE")
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM failure_codes WHERE %s ORDER BY code`,
+		failureCodeColumns, strings.Join(conditions, " AND "))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := []model.FailureCode{}
+	for rows.Next() {
+		var fc model.FailureCode
+		if err := scanFailureCode(rows, &fc); err != nil {
+			return nil, err
+		}
+		codes = append(codes, fc)
+	}
+
+	return codes, rows.Err()
+}
+
+// Create inserts a new failure code
+func (r *FailureCodeRepository) Create(ctx context.Context, fc *model.FailureCode) error {
+	query := `
+		INSERT INTO failure_codes (tenant_id, parent_id, level, code, description, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, fc.TenantID, fc.ParentID, fc.Level, fc.Code, fc.Description, fc.IsActive).
+		Scan(&fc.ID, &fc.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrFailureCodeExists
+	}
+	return err
+}
+
+// Update modifies a failure code's code, description and active flag. The level
+// and parent are fixed, so existing close-outs keep a consistent hierarchy.
+func (r *FailureCodeRepository) Update(ctx context.Context, fc *model.FailureCode) error {
+	query := `
+		UPDATE failure_codes
+		SET code = $2, description = $3, is_active = $4
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, fc.ID, fc.Code, fc.Description, fc.IsActive)
+	if isUniqueViolation(err) {
+		return ErrFailureCodeExists
+	}
+	return err
+}
+
+// Delete removes a failure code. Codes with children or used on work orders
+// cannot be deleted; deactivate them instead.
+func (r *FailureCodeRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM failure_codes WHERE id = $1`, id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrFailureCodeInUse
+		}
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrFailureCodeNotFound
+	}
+	return nil
+}
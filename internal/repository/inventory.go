@@ -9,12 +9,14 @@ import (
 	"ioi-amms/internal/model"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var (
-	ErrPartNotFound  = errors.New("part not found")
-	ErrStockNotFound = errors.New("stock not found")
+	ErrPartNotFound      = errors.New("part not found")
+	ErrStockNotFound     = errors.New("stock not found")
+	ErrInsufficientStock = errors.New("insufficient quantity available")
 )
 
 // InventoryRepository handles parts and inventory data access
@@ -32,20 +34,20 @@ func NewInventoryRepository(db *pgxpool.Pool) *InventoryRepository {
 // CreatePart inserts a new part
 func (r *InventoryRepository) CreatePart(ctx context.Context, part *model.Part) error {
 	query := `
-		INSERT INTO parts (tenant_id, sku, name, category, uom, min_stock_level, is_stock_item)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO parts (tenant_id, sku, name, category, uom, min_stock_level, is_stock_item, unit_cost)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, created_at
 	`
 
 	return r.db.QueryRow(ctx, query,
-		part.TenantID, part.SKU, part.Name, part.Category, part.UOM, part.MinStockLevel, part.IsStockItem,
+		part.TenantID, part.SKU, part.Name, part.Category, part.UOM, part.MinStockLevel, part.IsStockItem, part.UnitCost,
 	).Scan(&part.ID, &part.CreatedAt)
 }
 
 // FindPartByID retrieves a part by ID
 func (r *InventoryRepository) FindPartByID(ctx context.Context, id string) (*model.Part, error) {
 	query := `
-		SELECT id, tenant_id, sku, name, category, uom, min_stock_level, is_stock_item, created_at
+		SELECT id, tenant_id, sku, name, category, uom, min_stock_level, is_stock_item, unit_cost, created_at
 		FROM parts
 		WHERE id = $1
 	`
@@ -53,7 +55,7 @@ func (r *InventoryRepository) FindPartByID(ctx context.Context, id string) (*mod
 	var part model.Part
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&part.ID, &part.TenantID, &part.SKU, &part.Name, &part.Category,
-		&part.UOM, &part.MinStockLevel, &part.IsStockItem, &part.CreatedAt,
+		&part.UOM, &part.MinStockLevel, &part.IsStockItem, &part.UnitCost, &part.CreatedAt,
 	)
 
 	if err != nil {
@@ -108,7 +110,7 @@ func (r *InventoryRepository) ListParts(ctx context.Context, params model.PartLi
 	offset := (params.Page - 1) * params.Limit
 
 	query := fmt.Sprintf(`
-		SELECT id, tenant_id, sku, name, category, uom, min_stock_level, is_stock_item, created_at
+		SELECT id, tenant_id, sku, name, category, uom, min_stock_level, is_stock_item, unit_cost, created_at
 		FROM parts
 		WHERE %s
 		ORDER BY name ASC
@@ -128,7 +130,7 @@ func (r *InventoryRepository) ListParts(ctx context.Context, params model.PartLi
 		var part model.Part
 		err := rows.Scan(
 			&part.ID, &part.TenantID, &part.SKU, &part.Name, &part.Category,
-			&part.UOM, &part.MinStockLevel, &part.IsStockItem, &part.CreatedAt,
+			&part.UOM, &part.MinStockLevel, &part.IsStockItem, &part.UnitCost, &part.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -151,12 +153,12 @@ func (r *InventoryRepository) ListParts(ctx context.Context, params model.PartLi
 func (r *InventoryRepository) UpdatePart(ctx context.Context, part *model.Part) error {
 	query := `
 		UPDATE parts
-		SET sku = $2, name = $3, category = $4, uom = $5, min_stock_level = $6, is_stock_item = $7
+		SET sku = $2, name = $3, category = $4, uom = $5, min_stock_level = $6, is_stock_item = $7, unit_cost = $8
 		WHERE id = $1
 	`
 
 	_, err := r.db.Exec(ctx, query,
-		part.ID, part.SKU, part.Name, part.Category, part.UOM, part.MinStockLevel, part.IsStockItem,
+		part.ID, part.SKU, part.Name, part.Category, part.UOM, part.MinStockLevel, part.IsStockItem, part.UnitCost,
 	)
 	return err
 }
@@ -229,7 +231,7 @@ func (r *InventoryRepository) ListStock(ctx context.Context, params model.Invent
 	query := fmt.Sprintf(`
 		SELECT 
 			s.id, s.tenant_id, s.part_id, s.location_id, s.quantity_on_hand, s.bin_label, s.updated_at,
-			p.name as part_name, p.sku as part_sku,
+			p.name as part_name, p.sku as part_sku, p.min_stock_level,
 			l.name as location_name
 		FROM inventory_stock s
 		JOIN parts p ON s.part_id = p.id
@@ -252,7 +254,7 @@ func (r *InventoryRepository) ListStock(ctx context.Context, params model.Invent
 		var s model.InventoryStock
 		err := rows.Scan(
 			&s.ID, &s.TenantID, &s.PartID, &s.LocationID, &s.QuantityOnHand, &s.BinLabel, &s.UpdatedAt,
-			&s.PartName, &s.PartSKU,
+			&s.PartName, &s.PartSKU, &s.MinStockLevel,
 			&s.LocationName,
 		)
 		if err != nil {
@@ -312,7 +314,7 @@ func (r *InventoryRepository) ListWallets(ctx context.Context, userID string) ([
 	query := `
 		SELECT 
 			w.user_id, w.part_id, w.qty_held, w.last_updated_at,
-			p.name as part_name, p.sku as part_sku
+			p.name as part_name, p.sku as part_sku, p.unit_cost
 		FROM inventory_wallets w
 		JOIN parts p ON w.part_id = p.id
 		WHERE w.user_id = $1
@@ -330,7 +332,7 @@ func (r *InventoryRepository) ListWallets(ctx context.Context, userID string) ([
 		var w model.InventoryWallet
 		err := rows.Scan(
 			&w.UserID, &w.PartID, &w.QtyHeld, &w.LastUpdatedAt,
-			&w.PartName, &w.PartSKU,
+			&w.PartName, &w.PartSKU, &w.UnitCost,
 		)
 		if err != nil {
 			return nil, err
@@ -367,3 +369,112 @@ func (r *InventoryRepository) AdjustWallet(ctx context.Context, userID, partID s
 	_, err := r.db.Exec(ctx, query, userID, partID, delta)
 	return err
 }
+
+// ==================== PART CONSUMPTION ====================
+
+// ConsumePart records a part used on a work order. In one transaction it inserts the
+// wo_resource_logs row, draws the quantity from the user's wallet (or from warehouse
+// stock at LocationID when the source is Warehouse) and writes the audit entry.
+// Returns ErrInsufficientStock rather than letting the quantity go negative.
+func (r *InventoryRepository) ConsumePart(ctx context.Context, tenantID string, rl *model.ResourceLog, audit *model.AuditLog) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var result pgconn.CommandTag
+	if rl.Source == model.ResourceSourceWarehouse {
+		// A location can hold the part in several bins; draw from exactly one, the
+		// fullest that covers the quantity
+		var stockID string
+		err = tx.QueryRow(ctx, `
+			SELECT id FROM inventory_stock
+			WHERE tenant_id = $1 AND part_id = $2 AND location_id = $3 AND quantity_on_hand >= $4
+			ORDER BY quantity_on_hand DESC, id
+			LIMIT 1
+			FOR UPDATE
+		`, tenantID, rl.PartID, rl.LocationID, rl.Quantity).Scan(&stockID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInsufficientStock
+			}
+			return err
+		}
+		audit.Changes["stockId"] = stockID
+
+		result, err = tx.Exec(ctx, `
+			UPDATE inventory_stock
+			SET quantity_on_hand = quantity_on_hand - $2, updated_at = NOW()
+			WHERE id = $1 AND quantity_on_hand >= $2
+		`, stockID, rl.Quantity)
+	} else {
+		result, err = tx.Exec(ctx, `
+			UPDATE inventory_wallets
+			SET qty_held = qty_held - $3, last_updated_at = NOW()
+			WHERE user_id = $1 AND part_id = $2 AND qty_held >= $3
+		`, rl.UserID, rl.PartID, rl.Quantity)
+	}
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() != 1 {
+		return ErrInsufficientStock
+	}
+
+	insert := `
+		INSERT INTO wo_resource_logs (work_order_id, part_name, part_id, quantity, source, user_id, location_id, unit_cost)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT unit_cost FROM parts WHERE id = $3))
+		RETURNING id, unit_cost, created_at
+	`
+	err = tx.QueryRow(ctx, insert,
+		rl.WorkOrderID, rl.PartName, rl.PartID, rl.Quantity, rl.Source, rl.UserID, rl.LocationID,
+	).Scan(&rl.ID, &rl.UnitCost, &rl.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	audit.Changes["resourceLogId"] = rl.ID
+	if err := insertAuditLog(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListResourceLogs retrieves the parts consumed on a work order, newest first
+func (r *InventoryRepository) ListResourceLogs(ctx context.Context, workOrderID string) ([]model.ResourceLog, error) {
+	query := `
+		SELECT
+			l.id, l.work_order_id, l.part_id, COALESCE(l.part_name, p.name), l.quantity,
+			COALESCE(l.source, 'Warehouse'), l.user_id, l.location_id, l.unit_cost, COALESCE(l.created_at, NOW()),
+			COALESCE(p.sku, ''), COALESCE(u.full_name, '')
+		FROM wo_resource_logs l
+		LEFT JOIN parts p ON l.part_id = p.id
+		LEFT JOIN users u ON l.user_id = u.id
+		WHERE l.work_order_id = $1
+		ORDER BY l.created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []model.ResourceLog{}
+	for rows.Next() {
+		var l model.ResourceLog
+		err := rows.Scan(
+			&l.ID, &l.WorkOrderID, &l.PartID, &l.PartName, &l.Quantity,
+			&l.Source, &l.UserID, &l.LocationID, &l.UnitCost, &l.CreatedAt,
+			&l.PartSKU, &l.UserName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+
+	return logs, rows.Err()
+}
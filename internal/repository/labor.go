@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrLaborLogNotFound = errors.New("labor log not found")
+)
+
+// laborRateExpr resolves a user's hourly rate: their own rate, else the tenant's rate
+// for their role, else the tenant's default (requires users u and tenants t)
+const laborRateExpr = `COALESCE(
+	u.hourly_rate,
+	(t.settings->'roleHourlyRates'->>u.role)::decimal,
+	(t.settings->>'defaultHourlyRate')::decimal,
+	0
+)`
+
+// LaborRepository handles work order labor log data access
+type LaborRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewLaborRepository creates a new labor log repository
+func NewLaborRepository(db *pgxpool.Pool) *LaborRepository {
+	return &LaborRepository{db: db}
+}
+
+const laborSelect = `
+	SELECT l.id, l.work_order_id, l.user_id, l.hours_spent, l.date_performed, l.comment,
+		COALESCE(u.full_name, u.email), ` + laborRateExpr + `
+	FROM wo_labor_logs l
+	JOIN users u ON u.id = l.user_id
+	JOIN tenants t ON t.id = u.tenant_id
+`
+
+// scanLaborLog scans a row selected with laborSelect and computes its cost
+func scanLaborLog(row pgx.Row, l *model.LaborLog) error {
+	err := row.Scan(&l.ID, &l.WorkOrderID, &l.UserID, &l.HoursSpent, &l.DatePerformed, &l.Comment,
+		&l.UserName, &l.HourlyRate)
+	if err != nil {
+		return err
+	}
+	l.Cost = l.HoursSpent * l.HourlyRate
+	return nil
+}
+
+// FindByID retrieves a labor log by ID
+func (r *LaborRepository) FindByID(ctx context.Context, id string) (*model.LaborLog, error) {
+	var l model.LaborLog
+	if err := scanLaborLog(r.db.QueryRow(ctx, laborSelect+" WHERE l.id = $1", id), &l); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLaborLogNotFound
+		}
+		return nil, err
+	}
+	return &l, nil
+}
+
+// ListByWorkOrder retrieves a work order's labor entries with their cost, and the totals
+func (r *LaborRepository) ListByWorkOrder(ctx context.Context, workOrderID string) ([]model.LaborLog, model.LaborSummary, error) {
+	var summary model.LaborSummary
+
+	rows, err := r.db.Query(ctx, laborSelect+" WHERE l.work_order_id = $1 ORDER BY l.date_performed, u.full_name", workOrderID)
+	if err != nil {
+		return nil, summary, err
+	}
+	defer rows.Close()
+
+	logs := []model.LaborLog{}
+	for rows.Next() {
+		var l model.LaborLog
+		if err := scanLaborLog(rows, &l); err != nil {
+			return nil, summary, err
+		}
+		summary.TotalHours += l.HoursSpent
+		summary.TotalCost += l.Cost
+		logs = append(logs, l)
+	}
+	summary.Entries = len(logs)
+
+	return logs, summary, rows.Err()
+}
+
+// Count returns the number of labor entries on a work order
+func (r *LaborRepository) Count(ctx context.Context, workOrderID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM wo_labor_logs WHERE work_order_id = $1`, workOrderID).Scan(&count)
+	return count, err
+}
+
+// Create inserts a new labor log. A zero DatePerformed defaults to today.
+func (r *LaborRepository) Create(ctx context.Context, l *model.LaborLog) error {
+	var date interface{}
+	if !l.DatePerformed.IsZero() {
+		date = l.DatePerformed
+	}
+
+	query := `
+		INSERT INTO wo_labor_logs (work_order_id, user_id, hours_spent, date_performed, comment)
+		VALUES ($1, $2, $3, COALESCE($4::date, CURRENT_DATE), $5)
+		RETURNING id, date_performed
+	`
+
+	return r.db.QueryRow(ctx, query, l.WorkOrderID, l.UserID, l.HoursSpent, date, l.Comment).
+		Scan(&l.ID, &l.DatePerformed)
+}
+
+// Update modifies an existing labor log
+func (r *LaborRepository) Update(ctx context.Context, l *model.LaborLog) error {
+	query := `
+		UPDATE wo_labor_logs
+		SET hours_spent = $2, date_performed = $3, comment = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, l.ID, l.HoursSpent, l.DatePerformed, l.Comment)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrLaborLogNotFound
+	}
+	return nil
+}
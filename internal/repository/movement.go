@@ -0,0 +1,532 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrMovementNotFound       = errors.New("asset movement not found")
+	ErrMovementStatusConflict = errors.New("asset movement status changed concurrently")
+	ErrShipmentNotFound       = errors.New("shipment not found")
+	ErrNothingToTransfer      = errors.New("assets are already at the destination")
+	ErrShipmentNotInTransit   = errors.New("shipment has nothing in transit")
+)
+
+// movementColumns and movementJoins select a movement with its asset, location and
+// requester names, in the order read by scanMovement
+const (
+	movementColumns = `
+			m.id, m.tenant_id, m.asset_id, m.from_location_id, m.to_location_id,
+			COALESCE(m.status, ''), COALESCE(m.transfer_type, ''), COALESCE(m.shipment_ref, ''), m.notes,
+			m.created_by_user_id, m.reviewed_by_user_id, m.reviewed_at, m.review_note,
+			m.received_by_user_id, m.received_at,
+			m.disputed_by_user_id, m.disputed_at, m.dispute_reason,
+			COALESCE(m.created_at, NOW()),
+			a.name, a.parent_id,
+			COALESCE(fl.name, ''), COALESCE(tl.name, ''), COALESCE(u.full_name, u.email, '')`
+	movementJoins = `
+		JOIN assets a ON a.id = m.asset_id
+		LEFT JOIN locations fl ON fl.id = m.from_location_id
+		LEFT JOIN locations tl ON tl.id = m.to_location_id
+		LEFT JOIN users u ON u.id = m.created_by_user_id`
+)
+
+// MovementRepository handles asset movements (chain of custody)
+type MovementRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewMovementRepository creates a new asset movement repository
+func NewMovementRepository(db *pgxpool.Pool) *MovementRepository {
+	return &MovementRepository{db: db}
+}
+
+func scanMovement(row pgx.Row, m *model.AssetMovement) error {
+	return row.Scan(
+		&m.ID, &m.TenantID, &m.AssetID, &m.FromLocationID, &m.ToLocationID,
+		&m.Status, &m.TransferType, &m.ShipmentRef, &m.Notes,
+		&m.CreatedByUserID, &m.ReviewedByUserID, &m.ReviewedAt, &m.ReviewNote,
+		&m.ReceivedByUserID, &m.ReceivedAt,
+		&m.DisputedByUserID, &m.DisputedAt, &m.DisputeReason,
+		&m.CreatedAt,
+		&m.AssetName, &m.AssetParentID,
+		&m.FromLocationName, &m.ToLocationName, &m.CreatedByName,
+	)
+}
+
+// queryMovements runs a movement query and collects the rows
+func queryMovements(ctx context.Context, q interface {
+	Query(context.Context, string, ...interface{}) (pgx.Rows, error)
+}, query string, args ...interface{}) ([]model.AssetMovement, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []model.AssetMovement{}
+	for rows.Next() {
+		var m model.AssetMovement
+		if err := scanMovement(rows, &m); err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+// CreateTransfer ships the requested assets, each with everything below it, to a
+// location in one transaction: one movement per asset not already there, under a
+// new shipment reference. The destination and assets must belong to the tenant,
+// and no asset may have an open transfer.
+func (r *MovementRepository) CreateTransfer(ctx context.Context, req model.TransferRequest) (string, []model.AssetMovement, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var ok bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM locations WHERE id = $1 AND tenant_id = $2)`,
+		req.ToLocationID, req.TenantID).Scan(&ok)
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok {
+		return "", nil, ErrLocationNotFound
+	}
+
+	if err := lockAssetHierarchy(ctx, tx, req.TenantID); err != nil {
+		return "", nil, err
+	}
+
+	var found int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM assets WHERE id = ANY($1) AND tenant_id = $2`,
+		req.AssetIDs, req.TenantID).Scan(&found)
+	if err != nil {
+		return "", nil, err
+	}
+	if found != len(req.AssetIDs) {
+		return "", nil, ErrAssetNotFound
+	}
+
+	// The requested assets and everything below them
+	const forest = `
+		WITH RECURSIVE subtree AS (
+			SELECT id, ARRAY[id] AS path FROM assets WHERE id = ANY($1) AND tenant_id = $2
+			UNION ALL
+			SELECT c.id, s.path || c.id
+			FROM assets c JOIN subtree s ON c.parent_id = s.id
+			WHERE NOT c.id = ANY(s.path)
+		)`
+
+	var open bool
+	err = tx.QueryRow(ctx, forest+`
+		SELECT EXISTS (
+			SELECT 1 FROM asset_movements m JOIN subtree s ON m.asset_id = s.id
+			WHERE m.status = ANY($3)
+		)
+	`, req.AssetIDs, req.TenantID, model.OpenMovementStatuses).Scan(&open)
+	if err != nil {
+		return "", nil, err
+	}
+	if open {
+		return "", nil, ErrAssetInTransit
+	}
+
+	ref := model.NewShipmentRef(time.Now())
+	result, err := tx.Exec(ctx, forest+`
+		INSERT INTO asset_movements (tenant_id, asset_id, from_location_id, to_location_id, status,
+			transfer_type, shipment_ref, notes, created_by_user_id)
+		SELECT $2, a.id, a.location_id, $3, $4, $5, $6, $7, $8::uuid
+		FROM assets a
+		WHERE a.id IN (SELECT id FROM subtree) AND a.location_id IS DISTINCT FROM $3
+	`, req.AssetIDs, req.TenantID, req.ToLocationID, req.Status, req.TransferType, ref, req.Notes, req.UserID)
+	if err != nil {
+		return "", nil, err
+	}
+	if result.RowsAffected() == 0 {
+		return "", nil, ErrNothingToTransfer
+	}
+
+	movements, err := queryMovements(ctx, tx, `
+		SELECT `+movementColumns+`
+		FROM asset_movements m`+movementJoins+`
+		WHERE m.tenant_id = $1 AND m.shipment_ref = $2
+		ORDER BY a.name, m.id
+	`, req.TenantID, ref)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ref, movements, tx.Commit(ctx)
+}
+
+// FindByID retrieves a movement
+func (r *MovementRepository) FindByID(ctx context.Context, id string) (*model.AssetMovement, error) {
+	query := `
+		SELECT ` + movementColumns + `
+		FROM asset_movements m` + movementJoins + `
+		WHERE m.id = $1
+	`
+
+	var m model.AssetMovement
+	if err := scanMovement(r.db.QueryRow(ctx, query, id), &m); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMovementNotFound
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List retrieves movements with filtering and pagination, newest first
+func (r *MovementRepository) List(ctx context.Context, params model.AssetMovementListParams) (*model.PaginatedResult[model.AssetMovement], error) {
+	var conditions []string
+	var args []interface{}
+	argNum := 1
+
+	conditions = append(conditions, fmt.Sprintf("m.tenant_id = $%d", argNum))
+	args = append(args, params.TenantID)
+	argNum++
+
+	if len(params.Status) > 0 {
+		conditions = append(conditions, fmt.Sprintf("m.status = ANY($%d)", argNum))
+		args = append(args, params.Status)
+		argNum++
+	}
+
+	if params.ShipmentRef != "" {
+		conditions = append(conditions, fmt.Sprintf("m.shipment_ref = $%d", argNum))
+		args = append(args, params.ShipmentRef)
+		argNum++
+	}
+
+	if params.AssetID != "" {
+		conditions = append(conditions, fmt.Sprintf("m.asset_id = $%d", argNum))
+		args = append(args, params.AssetID)
+		argNum++
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM asset_movements m WHERE "+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	offset := (params.Page - 1) * params.Limit
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM asset_movements m%s
+		WHERE %s
+		ORDER BY m.created_at DESC, m.shipment_ref, a.name
+		LIMIT $%d OFFSET $%d
+	`, movementColumns, movementJoins, whereClause, argNum, argNum+1)
+	args = append(args, params.Limit, offset)
+
+	movements, err := queryMovements(ctx, r.db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PaginatedResult[model.AssetMovement]{
+		Data:       movements,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: (total + params.Limit - 1) / params.Limit,
+	}, nil
+}
+
+// ListShipment retrieves every movement of a shipment of the tenant, by asset name
+func (r *MovementRepository) ListShipment(ctx context.Context, tenantID, ref string) ([]model.AssetMovement, error) {
+	movements, err := queryMovements(ctx, r.db, `
+		SELECT `+movementColumns+`
+		FROM asset_movements m`+movementJoins+`
+		WHERE m.tenant_id = $1 AND m.shipment_ref = $2
+		ORDER BY a.name, m.id
+	`, tenantID, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(movements) == 0 {
+		return nil, ErrShipmentNotFound
+	}
+	return movements, nil
+}
+
+// ListShipments summarizes the tenant's shipments that have a movement in one of
+// the given statuses, newest first
+func (r *MovementRepository) ListShipments(ctx context.Context, tenantID string, statuses []string, page, limit int) ([]model.Shipment, int, error) {
+	query := `
+		SELECT m.shipment_ref, MIN(m.transfer_type),
+			(ARRAY_AGG(m.to_location_id))[1], COALESCE(MIN(tl.name), ''),
+			(ARRAY_AGG(m.created_by_user_id))[1], COALESCE(MIN(COALESCE(u.full_name, u.email)), ''),
+			MIN(m.created_at), COUNT(*),
+			COUNT(*) FILTER (WHERE m.status = 'Pending_Approval'),
+			COUNT(*) FILTER (WHERE m.status = 'In_Transit'),
+			COUNT(*) FILTER (WHERE m.status = 'Received'),
+			COUNT(*) FILTER (WHERE m.status = 'Disputed'),
+			COUNT(*) FILTER (WHERE m.status = 'Rejected'),
+			COUNT(*) OVER ()
+		FROM asset_movements m
+		LEFT JOIN locations tl ON tl.id = m.to_location_id
+		LEFT JOIN users u ON u.id = m.created_by_user_id
+		WHERE m.tenant_id = $1 AND m.shipment_ref IS NOT NULL
+		GROUP BY m.shipment_ref
+		HAVING COUNT(*) FILTER (WHERE m.status = ANY($2)) > 0
+		ORDER BY MIN(m.created_at) DESC, m.shipment_ref
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, statuses, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	shipments := []model.Shipment{}
+	total := 0
+	for rows.Next() {
+		var s model.Shipment
+		var transferType *string
+		var pending, inTransit, received, disputed, rejected int
+		err := rows.Scan(&s.ShipmentRef, &transferType,
+			&s.ToLocationID, &s.ToLocationName, &s.CreatedByUserID, &s.CreatedByName,
+			&s.CreatedAt, &s.Items,
+			&pending, &inTransit, &received, &disputed, &rejected,
+			&total)
+		if err != nil {
+			return nil, 0, err
+		}
+		if transferType != nil {
+			s.TransferType = *transferType
+		}
+		s.StatusCounts = map[string]int{
+			model.MovementStatusPendingApproval: pending,
+			model.MovementStatusInTransit:       inTransit,
+			model.MovementStatusReceived:        received,
+			model.MovementStatusDisputed:        disputed,
+			model.MovementStatusRejected:        rejected,
+		}
+		shipments = append(shipments, s)
+	}
+
+	return shipments, total, rows.Err()
+}
+
+// ReviewShipment approves (In_Transit) or rejects (Rejected) every movement of the
+// shipment awaiting approval, returning the shipment's movements. A shipment with
+// nothing awaiting approval gives ErrMovementStatusConflict.
+func (r *MovementRepository) ReviewShipment(ctx context.Context, tenantID, ref, status, userID string, note *string) ([]model.AssetMovement, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE asset_movements
+		SET status = $3, reviewed_by_user_id = $4, reviewed_at = NOW(), review_note = $5
+		WHERE tenant_id = $1 AND shipment_ref = $2 AND status = 'Pending_Approval'
+	`, tenantID, ref, status, userID, note)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := queryMovements(ctx, tx, `
+		SELECT `+movementColumns+`
+		FROM asset_movements m`+movementJoins+`
+		WHERE m.tenant_id = $1 AND m.shipment_ref = $2
+		ORDER BY a.name, m.id
+	`, tenantID, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(movements) == 0 {
+		return nil, ErrShipmentNotFound
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrMovementStatusConflict
+	}
+
+	return movements, tx.Commit(ctx)
+}
+
+// Receive marks a movement read with its current status Received by userID and
+// puts the asset at the destination, in one transaction
+func (r *MovementRepository) Receive(ctx context.Context, m *model.AssetMovement, userID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := receiveMovement(ctx, tx, m, userID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// receiveMovement marks the movement Received and moves its asset, inside tx
+func receiveMovement(ctx context.Context, tx pgx.Tx, m *model.AssetMovement, userID string) error {
+	err := tx.QueryRow(ctx, `
+		UPDATE asset_movements
+		SET status = 'Received', received_by_user_id = $3, received_at = NOW()
+		WHERE id = $1 AND status = $2
+		RETURNING status, received_by_user_id, received_at
+	`, m.ID, m.Status, userID).Scan(&m.Status, &m.ReceivedByUserID, &m.ReceivedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrMovementStatusConflict
+		}
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE assets SET location_id = $2, updated_at = NOW() WHERE id = $1`, m.AssetID, m.ToLocationID)
+	return err
+}
+
+// Dispute marks a movement read with its current status Disputed by userID
+func (r *MovementRepository) Dispute(ctx context.Context, m *model.AssetMovement, reason, userID string) error {
+	return disputeMovement(ctx, r.db, m, reason, userID)
+}
+
+// disputeMovement marks the movement Disputed through q
+func disputeMovement(ctx context.Context, q rowQuerier, m *model.AssetMovement, reason, userID string) error {
+	err := q.QueryRow(ctx, `
+		UPDATE asset_movements
+		SET status = 'Disputed', disputed_by_user_id = $3, disputed_at = NOW(), dispute_reason = $4
+		WHERE id = $1 AND status = $2
+		RETURNING status, disputed_by_user_id, disputed_at, dispute_reason
+	`, m.ID, m.Status, userID, reason).Scan(&m.Status, &m.DisputedByUserID, &m.DisputedAt, &m.DisputeReason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrMovementStatusConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// ReceiveShipment receives every In_Transit movement of a shipment in one
+// transaction, putting the assets at the destination. Movements of the assets in
+// missing are disputed with reason instead, and everything not received is
+// reported as an exception. A shipment with nothing in transit gives
+// ErrShipmentNotInTransit.
+func (r *MovementRepository) ReceiveShipment(ctx context.Context, tenantID, ref string, missing []string, reason, userID string) (*model.ShipmentReceipt, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	movements, err := queryMovements(ctx, tx, `
+		SELECT `+movementColumns+`
+		FROM asset_movements m`+movementJoins+`
+		WHERE m.tenant_id = $1 AND m.shipment_ref = $2
+		ORDER BY a.name, m.id
+		FOR UPDATE OF m
+	`, tenantID, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(movements) == 0 {
+		return nil, ErrShipmentNotFound
+	}
+
+	isMissing := make(map[string]bool, len(missing))
+	for _, id := range missing {
+		isMissing[id] = true
+	}
+
+	receipt := &model.ShipmentReceipt{ShipmentRef: ref, Exceptions: []model.ReceiptException{}, Items: movements}
+	inTransit := 0
+	for i := range movements {
+		m := &movements[i]
+		exception := model.ReceiptException{MovementID: m.ID, AssetID: m.AssetID, AssetName: m.AssetName}
+		switch {
+		case m.Status != model.MovementStatusInTransit:
+			exception.Status = m.Status
+			exception.Reason = model.ReceiptExceptionNotInTransit
+		case isMissing[m.AssetID]:
+			inTransit++
+			if err := disputeMovement(ctx, tx, m, reason, userID); err != nil {
+				return nil, err
+			}
+			exception.Status = m.Status
+			exception.Reason = model.ReceiptExceptionMissing
+		default:
+			inTransit++
+			if err := receiveMovement(ctx, tx, m, userID); err != nil {
+				return nil, err
+			}
+			receipt.Received++
+			continue
+		}
+		delete(isMissing, m.AssetID)
+		receipt.Exceptions = append(receipt.Exceptions, exception)
+	}
+	if inTransit == 0 {
+		return nil, ErrShipmentNotInTransit
+	}
+
+	// Reported missing but not part of the shipment
+	for _, id := range missing {
+		if isMissing[id] {
+			delete(isMissing, id)
+			receipt.Exceptions = append(receipt.Exceptions, model.ReceiptException{
+				AssetID: id, Reason: model.ReceiptExceptionNotInShipment,
+			})
+		}
+	}
+
+	return receipt, tx.Commit(ctx)
+}
+
+// ListShipmentIdentities retrieves the identities of a shipment's assets, primary
+// first, keyed by asset ID
+func (r *MovementRepository) ListShipmentIdentities(ctx context.Context, tenantID, ref string) (map[string][]model.AssetIdentity, error) {
+	query := `
+		SELECT i.asset_id, i.type, i.value, COALESCE(i.is_primary, false)
+		FROM asset_identities i
+		WHERE i.asset_id IN (
+			SELECT asset_id FROM asset_movements WHERE tenant_id = $1 AND shipment_ref = $2
+		)
+		ORDER BY i.asset_id, COALESCE(i.is_primary, false) DESC, i.type
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := map[string][]model.AssetIdentity{}
+	for rows.Next() {
+		var i model.AssetIdentity
+		if err := rows.Scan(&i.AssetID, &i.Type, &i.Value, &i.IsPrimary); err != nil {
+			return nil, err
+		}
+		identities[i.AssetID] = append(identities[i.AssetID], i)
+	}
+	return identities, rows.Err()
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// NotificationRepository handles in-app notification data access
+type NotificationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateForRole sends n to every active user of the tenant with the given role,
+// returning how many users were notified
+func (r *NotificationRepository) CreateForRole(ctx context.Context, tenantID, role string, n *model.Notification) (int, error) {
+	query := `
+		INSERT INTO notifications (tenant_id, user_id, kind, work_order_id, message)
+		SELECT $1, u.id, $3, $4, $5
+		FROM users u
+		WHERE u.tenant_id = $1 AND u.role = $2 AND u.is_active = TRUE
+	`
+
+	result, err := r.db.Exec(ctx, query, tenantID, role, n.Kind, n.WorkOrderID, n.Message)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// List retrieves a user's latest notifications, newest first, and their unread count
+func (r *NotificationRepository) List(ctx context.Context, userID string, unreadOnly bool, limit int) ([]model.Notification, int, error) {
+	query := `
+		SELECT id, tenant_id, user_id, kind, work_order_id, message, created_at, read_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	notifications := []model.Notification{}
+	for rows.Next() {
+		var n model.Notification
+		err := rows.Scan(&n.ID, &n.TenantID, &n.UserID, &n.Kind, &n.WorkOrderID, &n.Message, &n.CreatedAt, &n.ReadAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var unread int
+	err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&unread)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, unread, nil
+}
+
+// MarkRead marks one of a user's notifications as read
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id string) error {
+	query := `
+		UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks all of a user's unread notifications as read
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string) (int, error) {
+	result, err := r.db.Exec(ctx, `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
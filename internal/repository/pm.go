@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrPMScheduleNotFound = errors.New("pm schedule not found")
+)
+
+// PMRepository handles preventive maintenance schedule data access
+type PMRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPMRepository creates a new PM schedule repository
+func NewPMRepository(db *pgxpool.Pool) *PMRepository {
+	return &PMRepository{db: db}
+}
+
+// pmScheduleSelect joins the owning asset (for tenant scoping), its meters and any open work order
+const pmScheduleSelect = `
+	SELECT
+		p.id, p.asset_id, p.checklist_template_id, p.title,
+		p.interval_days, p.interval_run_hours, p.interval_odometer_km,
+		p.last_performed_date, p.last_performed_run_hours, p.last_performed_odometer_km,
+		p.suppress_pm_ids, p.is_active,
+		a.tenant_id, a.name,
+		COALESCE(m.current_run_hours, 0), COALESCE(m.current_odometer_km, 0),
+		(
+			SELECT w.id FROM work_orders w
+			WHERE w.pm_schedule_id = p.id AND w.status NOT IN ('Closed', 'Cancelled')
+			LIMIT 1
+		) as open_work_order_id
+	FROM pm_schedules p
+	JOIN assets a ON p.asset_id = a.id
+	LEFT JOIN asset_meters m ON m.asset_id = p.asset_id
+`
+
+// scanPMSchedule scans a row selected with pmScheduleSelect
+func scanPMSchedule(row pgx.Row, s *model.PMSchedule) error {
+	var suppressJSON []byte
+	err := row.Scan(
+		&s.ID, &s.AssetID, &s.ChecklistTemplateID, &s.Title,
+		&s.IntervalDays, &s.IntervalRunHours, &s.IntervalOdometerKm,
+		&s.LastPerformedDate, &s.LastPerformedRunHours, &s.LastPerformedOdometerKm,
+		&suppressJSON, &s.IsActive,
+		&s.TenantID, &s.AssetName,
+		&s.CurrentRunHours, &s.CurrentOdometerKm,
+		&s.OpenWorkOrderID,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.SuppressPMIDs = []string{}
+	if len(suppressJSON) > 0 {
+		if err := json.Unmarshal(suppressJSON, &s.SuppressPMIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindByID retrieves a PM schedule by ID
+func (r *PMRepository) FindByID(ctx context.Context, id string) (*model.PMSchedule, error) {
+	var s model.PMSchedule
+	err := scanPMSchedule(r.db.QueryRow(ctx, pmScheduleSelect+" WHERE p.id = $1", id), &s)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPMScheduleNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// List retrieves PM schedules for a tenant, optionally filtered by asset
+func (r *PMRepository) List(ctx context.Context, tenantID, assetID string) ([]model.PMSchedule, error) {
+	conditions := []string{"a.tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if assetID != "" {
+		conditions = append(conditions, "p.asset_id = $2")
+		args = append(args, assetID)
+	}
+
+	query := fmt.Sprintf("%s WHERE %s ORDER BY a.name, p.title", pmScheduleSelect, strings.Join(conditions, " AND "))
+	return r.query(ctx, query, args...)
+}
+
+// ListForEvaluation retrieves active schedules to evaluate. An empty tenantID evaluates all tenants.
+func (r *PMRepository) ListForEvaluation(ctx context.Context, tenantID string) ([]model.PMSchedule, error) {
+	query := pmScheduleSelect + " WHERE p.is_active = TRUE AND a.status NOT IN ('Archived', 'Draft')"
+	if tenantID == "" {
+		return r.query(ctx, query)
+	}
+	return r.query(ctx, query+" AND a.tenant_id = $1", tenantID)
+}
+
+func (r *PMRepository) query(ctx context.Context, query string, args ...interface{}) ([]model.PMSchedule, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []model.PMSchedule
+	for rows.Next() {
+		var s model.PMSchedule
+		if err := scanPMSchedule(rows, &s); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+
+	return schedules, rows.Err()
+}
+
+// Create inserts a new PM schedule. A missing baseline defaults to today and the
+// asset's current meter readings, so a new schedule starts counting from now.
+func (r *PMRepository) Create(ctx context.Context, s *model.PMSchedule) error {
+	suppressJSON, err := json.Marshal(s.SuppressPMIDs)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO pm_schedules (asset_id, checklist_template_id, title,
+			interval_days, interval_run_hours, interval_odometer_km,
+			last_performed_date, last_performed_run_hours, last_performed_odometer_km,
+			suppress_pm_ids, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6,
+			COALESCE($7, CURRENT_DATE),
+			COALESCE($8, (SELECT current_run_hours FROM asset_meters WHERE asset_id = $1), 0),
+			COALESCE($9, (SELECT current_odometer_km FROM asset_meters WHERE asset_id = $1), 0),
+			$10, $11)
+		RETURNING id, last_performed_date, last_performed_run_hours, last_performed_odometer_km
+	`
+
+	return r.db.QueryRow(ctx, query,
+		s.AssetID, s.ChecklistTemplateID, s.Title,
+		s.IntervalDays, s.IntervalRunHours, s.IntervalOdometerKm,
+		s.LastPerformedDate, s.LastPerformedRunHours, s.LastPerformedOdometerKm,
+		suppressJSON, s.IsActive,
+	).Scan(&s.ID, &s.LastPerformedDate, &s.LastPerformedRunHours, &s.LastPerformedOdometerKm)
+}
+
+// Update modifies an existing PM schedule
+func (r *PMRepository) Update(ctx context.Context, s *model.PMSchedule) error {
+	suppressJSON, err := json.Marshal(s.SuppressPMIDs)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE pm_schedules
+		SET checklist_template_id = $2, title = $3,
+			interval_days = $4, interval_run_hours = $5, interval_odometer_km = $6,
+			last_performed_date = $7, last_performed_run_hours = $8, last_performed_odometer_km = $9,
+			suppress_pm_ids = $10, is_active = $11
+		WHERE id = $1
+	`
+
+	_, err = r.db.Exec(ctx, query,
+		s.ID, s.ChecklistTemplateID, s.Title,
+		s.IntervalDays, s.IntervalRunHours, s.IntervalOdometerKm,
+		s.LastPerformedDate, s.LastPerformedRunHours, s.LastPerformedOdometerKm,
+		suppressJSON, s.IsActive,
+	)
+	return err
+}
+
+// Delete removes a PM schedule
+func (r *PMRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM pm_schedules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrPMScheduleNotFound
+	}
+	return nil
+}
+
+// resetBaseline restarts the countdown of a PM work order's schedule from today
+// and the asset's current meters, inside tx. The schedules the work order suppressed
+// on the same asset are reset too, since the larger service covered their work.
+// Returns the IDs of every schedule that was reset.
+func resetBaseline(ctx context.Context, tx pgx.Tx, workOrderID string) ([]string, error) {
+	query := `
+		WITH targets AS (
+			SELECT s.id, s.asset_id
+			FROM work_orders w
+			JOIN pm_schedules p ON p.id = w.pm_schedule_id
+			JOIN pm_schedules s ON s.id = p.id
+				OR (s.asset_id = p.asset_id AND w.suppressed_pm_ids @> to_jsonb(s.id::text))
+			WHERE w.id = $1
+		)
+		UPDATE pm_schedules p
+		SET last_performed_date = CURRENT_DATE,
+			last_performed_run_hours = COALESCE(m.current_run_hours, 0),
+			last_performed_odometer_km = COALESCE(m.current_odometer_km, 0)
+		FROM targets t
+		LEFT JOIN asset_meters m ON m.asset_id = t.asset_id
+		WHERE p.id = t.id
+		RETURNING p.id
+	`
+
+	rows, err := tx.Query(ctx, query, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var resetID string
+		if err := rows.Scan(&resetID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, resetID)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrTaskNotFound = errors.New("task not found")
+)
+
+// TaskRepository handles work order task (wo_tasks) data access
+type TaskRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTaskRepository creates a new work order task repository
+func NewTaskRepository(db *pgxpool.Pool) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+// taskColumns is the select list shared by task queries (requires alias t)
+const taskColumns = `
+	t.id, t.work_order_id, t.description, t.task_type, COALESCE(t.is_mandatory, FALSE), COALESCE(t.sort_order, 0),
+	t.min_value, t.max_value, t.result_value, t.result_notes, t.photo_url, t.completed_at, t.completed_by_user_id,
+	(SELECT f.id FROM work_orders f WHERE f.source_task_id = t.id) as follow_up_work_order_id
+`
+
+// scanTask scans a row selected with taskColumns
+func scanTask(row pgx.Row, t *model.WOTask) error {
+	err := row.Scan(
+		&t.ID, &t.WorkOrderID, &t.Description, &t.TaskType, &t.IsMandatory, &t.SortOrder,
+		&t.MinValue, &t.MaxValue, &t.ResultValue, &t.ResultNotes, &t.PhotoURL, &t.CompletedAt, &t.CompletedByUserID,
+		&t.FollowUpWorkOrderID,
+	)
+	if err != nil {
+		return err
+	}
+	t.Failed = t.IsFailed()
+	return nil
+}
+
+// FindByID retrieves a task by ID
+func (r *TaskRepository) FindByID(ctx context.Context, id string) (*model.WOTask, error) {
+	query := `SELECT ` + taskColumns + ` FROM wo_tasks t WHERE t.id = $1`
+
+	var t model.WOTask
+	if err := scanTask(r.db.QueryRow(ctx, query, id), &t); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListByWorkOrder retrieves a work order's tasks in execution order
+func (r *TaskRepository) ListByWorkOrder(ctx context.Context, workOrderID string) ([]model.WOTask, error) {
+	query := `SELECT ` + taskColumns + ` FROM wo_tasks t WHERE t.work_order_id = $1 ORDER BY t.sort_order, t.description`
+
+	rows, err := r.db.Query(ctx, query, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []model.WOTask{}
+	for rows.Next() {
+		var t model.WOTask
+		if err := scanTask(rows, &t); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, rows.Err()
+}
+
+// SaveResult records a task's result and stamps who completed it and when
+func (r *TaskRepository) SaveResult(ctx context.Context, t *model.WOTask) error {
+	query := `
+		UPDATE wo_tasks
+		SET result_value = $2, result_notes = $3, photo_url = $4,
+			completed_at = NOW(), completed_by_user_id = $5
+		WHERE id = $1
+		RETURNING completed_at
+	`
+
+	err := r.db.QueryRow(ctx, query, t.ID, t.ResultValue, t.ResultNotes, t.PhotoURL, t.CompletedByUserID).Scan(&t.CompletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTaskNotFound
+		}
+		return err
+	}
+	t.Failed = t.IsFailed()
+	return nil
+}
+
+// CountOpenMandatory counts the mandatory tasks of a work order that are not completed yet
+func (r *TaskRepository) CountOpenMandatory(ctx context.Context, workOrderID string) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM wo_tasks
+		WHERE work_order_id = $1 AND is_mandatory = TRUE AND completed_at IS NULL
+	`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, workOrderID).Scan(&count)
+	return count, err
+}
@@ -30,8 +30,29 @@ type CategoryDefault struct {
 	DepreciationMethod   string `json:"depreciationMethod"` // e.g. "Straight Line"
 }
 
+// SLAPolicy sets how soon a work order of one priority must be started and completed
+type SLAPolicy struct {
+	RespondHours float64 `json:"respondHours"`
+	ResolveHours float64 `json:"resolveHours"`
+}
+
 // TenantSettings represents the JSONB settings structure
 type TenantSettings struct {
+	// Workflow: lets Technicians self-approve and close their own work orders
+	AgileMode bool `json:"agile_mode"`
+	// Workflow: closing a work order requires at least one labor entry
+	SmartCloseout bool `json:"smartCloseout"`
+
+	// Labor costing: users without their own hourly rate use their role's rate, then the default
+	DefaultHourlyRate float64            `json:"defaultHourlyRate"`
+	RoleHourlyRates   map[string]float64 `json:"roleHourlyRates"`
+
+	// Work order SLA targets per priority; priorities without a policy have no deadlines
+	SLAPolicies map[string]SLAPolicy `json:"slaPolicies"`
+
+	// Dashboard: wallet value above which a user appears on Wallet Watch
+	WalletWatchThreshold *float64 `json:"walletWatchThreshold"`
+
 	// Global Security Policies
 	SessionTimeoutMinutes int  `json:"sessionTimeoutMinutes"`
 	RequireMFA            bool `json:"requireMFA"`
@@ -60,6 +81,18 @@ type Tenant struct {
 	Settings map[string]interface{} `json:"settings"`
 }
 
+// AgileMode reports whether the tenant has the "Agile Toggle" enabled
+func (t *Tenant) AgileMode() bool {
+	agile, _ := t.Settings["agile_mode"].(bool)
+	return agile
+}
+
+// SmartCloseout reports whether closing a work order requires logged labor
+func (t *Tenant) SmartCloseout() bool {
+	enabled, _ := t.Settings["smartCloseout"].(bool)
+	return enabled
+}
+
 // GetSettings retrieves tenant settings
 func (r *TenantRepository) GetSettings(ctx context.Context, id string) (*Tenant, error) {
 	query := `
@@ -99,7 +132,7 @@ func (r *TenantRepository) UpdateSettings(ctx context.Context, id string, settin
 
 	query := `
 		UPDATE tenants
-		SET settings = settings || $2, updated_at = NOW()
+		SET settings = COALESCE(settings, '{}'::jsonb) || $2, updated_at = NOW()
 		WHERE id = $1
 		RETURNING id, name, settings
 	`
@@ -123,3 +156,12 @@ func (r *TenantRepository) UpdateSettings(ctx context.Context, id string, settin
 
 	return &t, nil
 }
+
+// IsAgileMode reports whether Agile Mode is enabled for a tenant
+func (r *TenantRepository) IsAgileMode(ctx context.Context, id string) (bool, error) {
+	t, err := r.GetSettings(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return t.AgileMode(), nil
+}
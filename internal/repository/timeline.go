@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"ioi-amms/internal/model"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TimelineRepository assembles work order activity from the audit log and child tables
+type TimelineRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTimelineRepository creates a new timeline repository
+func NewTimelineRepository(db *pgxpool.Pool) *TimelineRepository {
+	return &TimelineRepository{db: db}
+}
+
+// timelineQuery merges a work order's events, oldest first. Tasks, labor, parts,
+// follow-ups and comments come from their own tables; audit entries that only
+// duplicate those rows (and creation events) are skipped.
+const timelineQuery = `
+	SELECT e.kind, e.at, e.user_id, COALESCE(u.full_name, u.email, ''), e.ref_id, e.details
+	FROM (
+		SELECT 'created' AS kind, w.created_at AS at, w.requested_by_user_id AS user_id, w.id AS ref_id,
+			jsonb_build_object('origin', w.origin, 'priority', w.priority, 'title', w.title) AS details
+		FROM work_orders w
+		WHERE w.id = $1
+
+		UNION ALL
+		SELECT a.action, a.created_at, a.user_id, a.id, a.changes
+		FROM audit_logs a
+		WHERE a.entity_type = 'work_order' AND a.entity_id = $1
+			AND a.action NOT IN ('create', 'consume')
+			AND NOT (a.action = 'update' AND (a.changes ? 'taskId' OR a.changes ? 'commentId'))
+
+		UNION ALL
+		SELECT 'task', t.completed_at, t.completed_by_user_id, t.id,
+			jsonb_build_object('description', t.description, 'taskType', t.task_type,
+				'resultValue', t.result_value, 'resultNotes', t.result_notes, 'photoUrl', t.photo_url)
+		FROM wo_tasks t
+		WHERE t.work_order_id = $1 AND t.completed_at IS NOT NULL
+
+		UNION ALL
+		SELECT 'labor', COALESCE(l.created_at, l.date_performed::timestamp), l.user_id, l.id,
+			jsonb_build_object('hoursSpent', l.hours_spent, 'datePerformed', l.date_performed, 'comment', l.comment)
+		FROM wo_labor_logs l
+		WHERE l.work_order_id = $1
+
+		UNION ALL
+		SELECT 'part', rl.created_at, rl.user_id, rl.id,
+			jsonb_build_object('partId', rl.part_id, 'partName', COALESCE(p.name, rl.part_name),
+				'quantity', rl.quantity, 'source', rl.source)
+		FROM wo_resource_logs rl
+		LEFT JOIN parts p ON p.id = rl.part_id
+		WHERE rl.work_order_id = $1
+
+		UNION ALL
+		SELECT 'follow_up', f.created_at, f.requested_by_user_id, f.id,
+			jsonb_build_object('readableId', f.readable_id, 'title', f.title, 'priority', f.priority,
+				'status', f.status, 'sourceTaskId', f.source_task_id)
+		FROM work_orders f
+		WHERE f.parent_work_order_id = $1
+
+		UNION ALL
+		SELECT 'comment', c.created_at, c.user_id, c.id,
+			jsonb_build_object('body', c.body, 'parentId', c.parent_comment_id, 'editedAt', c.edited_at,
+				'mentions', ARRAY(SELECT m.user_id FROM wo_comment_mentions m WHERE m.comment_id = c.id))
+		FROM wo_comments c
+		WHERE c.work_order_id = $1
+	) e
+	LEFT JOIN users u ON u.id = e.user_id
+	WHERE e.at IS NOT NULL
+	ORDER BY e.at, e.kind
+`
+
+// ForWorkOrder returns a work order's activity timeline
+func (r *TimelineRepository) ForWorkOrder(ctx context.Context, workOrderID string) ([]model.TimelineEntry, error) {
+	rows, err := r.db.Query(ctx, timelineQuery, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.TimelineEntry{}
+	for rows.Next() {
+		var e model.TimelineEntry
+		var details []byte
+		if err := rows.Scan(&e.Kind, &e.At, &e.UserID, &e.UserName, &e.RefID, &details); err != nil {
+			return nil, err
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &e.Details); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
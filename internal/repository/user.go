@@ -28,7 +28,7 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 // FindByEmail retrieves a user by email (v1.1 schema)
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	query := `
-		SELECT id, tenant_id, org_unit_id, email, password_hash, full_name, role, is_active, created_at
+		SELECT id, tenant_id, org_unit_id, email, password_hash, full_name, role, is_active, hourly_rate, created_at
 		FROM users
 		WHERE email = $1
 	`
@@ -43,6 +43,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.
 		&user.FullName,
 		&user.Role,
 		&user.IsActive,
+		&user.HourlyRate,
 		&user.CreatedAt,
 	)
 
@@ -59,7 +60,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.
 // FindByID retrieves a user by ID (v1.1 schema)
 func (r *UserRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
 	query := `
-		SELECT id, tenant_id, org_unit_id, email, password_hash, full_name, role, is_active, created_at
+		SELECT id, tenant_id, org_unit_id, email, password_hash, full_name, role, is_active, hourly_rate, created_at
 		FROM users
 		WHERE id = $1
 	`
@@ -74,6 +75,7 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*model.User,
 		&user.FullName,
 		&user.Role,
 		&user.IsActive,
+		&user.HourlyRate,
 		&user.CreatedAt,
 	)
 
@@ -90,8 +92,8 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*model.User,
 // Create inserts a new user (v1.1 schema)
 func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 	query := `
-		INSERT INTO users (tenant_id, org_unit_id, email, password_hash, full_name, role, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (tenant_id, org_unit_id, email, password_hash, full_name, role, is_active, hourly_rate)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, created_at
 	`
 
@@ -103,6 +105,7 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 		user.FullName,
 		user.Role,
 		user.IsActive,
+		user.HourlyRate,
 	).Scan(&user.ID, &user.CreatedAt)
 }
 
@@ -110,7 +113,7 @@ func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 	query := `
 		UPDATE users
-		SET org_unit_id = $2, email = $3, full_name = $4, role = $5, is_active = $6
+		SET org_unit_id = $2, email = $3, full_name = $4, role = $5, is_active = $6, hourly_rate = $7
 		WHERE id = $1
 	`
 
@@ -121,6 +124,7 @@ func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 		user.FullName,
 		user.Role,
 		user.IsActive,
+		user.HourlyRate,
 	)
 	return err
 }
@@ -135,7 +139,7 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID, passwordHas
 // List retrieves all users for a tenant (v1.1 schema)
 func (r *UserRepository) List(ctx context.Context, tenantID string) ([]model.User, error) {
 	query := `
-		SELECT id, tenant_id, org_unit_id, email, password_hash, full_name, role, is_active, created_at
+		SELECT id, tenant_id, org_unit_id, email, password_hash, full_name, role, is_active, hourly_rate, created_at
 		FROM users
 		WHERE tenant_id = $1
 		ORDER BY created_at DESC
@@ -159,6 +163,7 @@ func (r *UserRepository) List(ctx context.Context, tenantID string) ([]model.Use
 			&user.FullName,
 			&user.Role,
 			&user.IsActive,
+			&user.HourlyRate,
 			&user.CreatedAt,
 		)
 		if err != nil {
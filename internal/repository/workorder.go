@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -9,11 +10,15 @@ import (
 	"ioi-amms/internal/model"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var (
-	ErrWorkOrderNotFound = errors.New("work order not found")
+	ErrWorkOrderNotFound       = errors.New("work order not found")
+	ErrWorkOrderStatusConflict = errors.New("work order status changed concurrently")
+	ErrOpenPMWorkOrderExists   = errors.New("an open work order already exists for this PM schedule")
+	ErrDefectFollowupExists    = errors.New("a follow-up work order already exists for this task")
 )
 
 // WorkOrderRepository handles work order data access
@@ -26,26 +31,68 @@ func NewWorkOrderRepository(db *pgxpool.Pool) *WorkOrderRepository {
 	return &WorkOrderRepository{db: db}
 }
 
+// slaResponseBreachedExpr is true while work has not started past its respondBy deadline
+const slaResponseBreachedExpr = `(w.respond_by < NOW() AND w.started_at IS NULL AND w.status IN ('Requested', 'Approved'))`
+
+// slaResolutionBreachedExpr is true while work is not complete past its resolveBy deadline
+const slaResolutionBreachedExpr = `(w.resolve_by < NOW() AND w.completed_at IS NULL AND w.status NOT IN ('Closed', 'Cancelled'))`
+
+// overdueExpr is true when a work order has breached either SLA deadline
+const overdueExpr = `(` + slaResponseBreachedExpr + ` OR ` + slaResolutionBreachedExpr + `)`
+
+// slaDeadlineExpr builds SQL computing an SLA deadline from the tenant's slaPolicies:
+// base plus the policy's hours for the priority, or NULL when there is no target.
+// target is "respondHours" or "resolveHours".
+func slaDeadlineExpr(base, tenantID, priority, target string) string {
+	return fmt.Sprintf(
+		"(%s + (SELECT (t.settings->'slaPolicies'->(%s)::text->>'%s')::float8 FROM tenants t WHERE t.id = %s) * INTERVAL '1 hour')",
+		base, priority, target, tenantID,
+	)
+}
+
+// workOrderColumns is the select list shared by work order queries (requires alias w and joined assets a)
+const workOrderColumns = `
+	w.id, w.tenant_id, w.readable_id, w.asset_id, w.assigned_user_id, w.requested_by_user_id,
+	w.pm_schedule_id, w.checklist_template_id, w.parent_work_order_id, w.source_task_id,
+	w.status, w.origin, w.priority, w.title,
+	w.problem_code_id, w.cause_code_id, w.remedy_code_id, w.closeout_notes,
+	w.description, w.started_at, w.completed_at, w.created_at,
+	w.respond_by, w.resolve_by, w.escalation_level, w.suppressed_pm_ids, ` + overdueExpr + ` as overdue,
+	COALESCE(a.name, '') as asset_name,
+	(SELECT COALESCE(SUM(l.hours_spent), 0) FROM wo_labor_logs l WHERE l.work_order_id = w.id) as labor_hours,
+	(
+		SELECT COALESCE(SUM(l.hours_spent * ` + laborRateExpr + `), 0)
+		FROM wo_labor_logs l
+		JOIN users u ON u.id = l.user_id
+		JOIN tenants t ON t.id = u.tenant_id
+		WHERE l.work_order_id = w.id
+	) as labor_cost
+`
+
+// scanWorkOrder scans a row selected with workOrderColumns
+func scanWorkOrder(row pgx.Row, wo *model.WorkOrder) error {
+	return row.Scan(
+		&wo.ID, &wo.TenantID, &wo.ReadableID, &wo.AssetID, &wo.AssignedUserID, &wo.RequestedByUserID,
+		&wo.PMScheduleID, &wo.ChecklistTemplateID, &wo.ParentWorkOrderID, &wo.SourceTaskID,
+		&wo.Status, &wo.Origin, &wo.Priority, &wo.Title,
+		&wo.ProblemCodeID, &wo.CauseCodeID, &wo.RemedyCodeID, &wo.CloseoutNotes,
+		&wo.Description, &wo.StartedAt, &wo.CompletedAt, &wo.CreatedAt,
+		&wo.RespondBy, &wo.ResolveBy, &wo.EscalationLevel, &wo.SuppressedPMIDs, &wo.Overdue,
+		&wo.AssetName, &wo.LaborHours, &wo.LaborCost,
+	)
+}
+
 // FindByID retrieves a work order by ID (v1.1 schema)
 func (r *WorkOrderRepository) FindByID(ctx context.Context, id string) (*model.WorkOrder, error) {
 	query := `
-		SELECT 
-			w.id, w.tenant_id, w.readable_id, w.asset_id, w.assigned_user_id,
-			w.status, w.origin, w.priority, w.title,
-			w.description, w.started_at, w.completed_at, w.created_at,
-			COALESCE(a.name, '') as asset_name
+		SELECT ` + workOrderColumns + `
 		FROM work_orders w
 		LEFT JOIN assets a ON w.asset_id = a.id
 		WHERE w.id = $1
 	`
 
 	var wo model.WorkOrder
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&wo.ID, &wo.TenantID, &wo.ReadableID, &wo.AssetID, &wo.AssignedUserID,
-		&wo.Status, &wo.Origin, &wo.Priority, &wo.Title,
-		&wo.Description, &wo.StartedAt, &wo.CompletedAt, &wo.CreatedAt,
-		&wo.AssetName,
-	)
+	err := scanWorkOrder(r.db.QueryRow(ctx, query, id), &wo)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -93,6 +140,20 @@ func (r *WorkOrderRepository) List(ctx context.Context, params model.WorkOrderLi
 		argNum++
 	}
 
+	if params.AssignedUserID != "" {
+		conditions = append(conditions, fmt.Sprintf("w.assigned_user_id = $%d", argNum))
+		args = append(args, params.AssignedUserID)
+		argNum++
+	}
+
+	if params.OpenOnly {
+		conditions = append(conditions, "w.status NOT IN ('Closed', 'Cancelled')")
+	}
+
+	if params.Overdue {
+		conditions = append(conditions, overdueExpr)
+	}
+
 	whereClause := strings.Join(conditions, " AND ")
 
 	// Count total
@@ -123,17 +184,13 @@ func (r *WorkOrderRepository) List(ctx context.Context, params model.WorkOrderLi
 	offset := (params.Page - 1) * params.Limit
 
 	query := fmt.Sprintf(`
-		SELECT 
-			w.id, w.tenant_id, w.readable_id, w.asset_id, w.assigned_user_id,
-			w.status, w.origin, w.priority, w.title,
-			w.description, w.started_at, w.completed_at, w.created_at,
-			COALESCE(a.name, '') as asset_name
+		SELECT %s
 		FROM work_orders w
 		LEFT JOIN assets a ON w.asset_id = a.id
 		WHERE %s
 		ORDER BY %s %s
 		LIMIT $%d OFFSET $%d
-	`, whereClause, sortBy, sortDir, argNum, argNum+1)
+	`, workOrderColumns, whereClause, sortBy, sortDir, argNum, argNum+1)
 
 	args = append(args, params.Limit, offset)
 
@@ -146,13 +203,7 @@ func (r *WorkOrderRepository) List(ctx context.Context, params model.WorkOrderLi
 	var workOrders []model.WorkOrder
 	for rows.Next() {
 		var wo model.WorkOrder
-		err := rows.Scan(
-			&wo.ID, &wo.TenantID, &wo.ReadableID, &wo.AssetID, &wo.AssignedUserID,
-			&wo.Status, &wo.Origin, &wo.Priority, &wo.Title,
-			&wo.Description, &wo.StartedAt, &wo.CompletedAt, &wo.CreatedAt,
-			&wo.AssetName,
-		)
-		if err != nil {
+		if err := scanWorkOrder(rows, &wo); err != nil {
 			return nil, err
 		}
 		workOrders = append(workOrders, wo)
@@ -169,34 +220,378 @@ func (r *WorkOrderRepository) List(ctx context.Context, params model.WorkOrderLi
 	}, nil
 }
 
-// Create inserts a new work order (v1.1 schema)
+// Create inserts a new work order (v1.1 schema). If a checklist template is set, its
+// items are expanded into ordered wo_tasks in the same transaction.
 func (r *WorkOrderRepository) Create(ctx context.Context, wo *model.WorkOrder) error {
+	if wo.SuppressedPMIDs == nil {
+		wo.SuppressedPMIDs = []string{}
+	}
+	suppressedJSON, err := json.Marshal(wo.SuppressedPMIDs)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// SLA deadlines are fixed at creation, so later policy changes do not move them
+	query := `
+		INSERT INTO work_orders (tenant_id, asset_id, assigned_user_id, requested_by_user_id, pm_schedule_id,
+			checklist_template_id, parent_work_order_id, source_task_id, status, origin, priority, title, description,
+			suppressed_pm_ids, respond_by, resolve_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+			` + slaDeadlineExpr("NOW()", "$1", "$11", "respondHours") + `,
+			` + slaDeadlineExpr("NOW()", "$1", "$11", "resolveHours") + `)
+		RETURNING id, readable_id, created_at, respond_by, resolve_by
+	`
+
+	err = tx.QueryRow(ctx, query,
+		wo.TenantID, wo.AssetID, wo.AssignedUserID, wo.RequestedByUserID, wo.PMScheduleID,
+		wo.ChecklistTemplateID, wo.ParentWorkOrderID, wo.SourceTaskID,
+		wo.Status, wo.Origin, wo.Priority, wo.Title, wo.Description, suppressedJSON,
+	).Scan(&wo.ID, &wo.ReadableID, &wo.CreatedAt, &wo.RespondBy, &wo.ResolveBy)
+	if err != nil {
+		if isUniqueViolation(err) {
+			switch {
+			case wo.SourceTaskID != nil:
+				return ErrDefectFollowupExists
+			case wo.PMScheduleID != nil:
+				return ErrOpenPMWorkOrderExists
+			}
+		}
+		return err
+	}
+
+	// A Critical work order means the asset is out of service
+	if wo.Priority == model.WOPriorityCritical && wo.AssetID != nil {
+		requester := ""
+		if wo.RequestedByUserID != nil {
+			requester = *wo.RequestedByUserID
+		}
+		if err := takeAssetDown(ctx, tx, wo, requester); err != nil {
+			return err
+		}
+	}
+
+	if wo.ChecklistTemplateID != nil {
+		// Items are copied, so later template edits do not change issued work orders
+		taskQuery := `
+			INSERT INTO wo_tasks (work_order_id, description, task_type, is_mandatory, sort_order, min_value, max_value)
+			SELECT $1, item->>'label', item->>'type', COALESCE((item->>'isMandatory')::boolean, FALSE), ord::int,
+				(item->>'minValue')::decimal, (item->>'maxValue')::decimal
+			FROM checklist_templates t,
+				jsonb_array_elements(t.items) WITH ORDINALITY AS items(item, ord)
+			WHERE t.id = $2 AND t.tenant_id = $3
+		`
+		if _, err := tx.Exec(ctx, taskQuery, wo.ID, *wo.ChecklistTemplateID, wo.TenantID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListFollowUps retrieves the work orders raised from the given work order, oldest first
+func (r *WorkOrderRepository) ListFollowUps(ctx context.Context, parentID string) ([]model.WorkOrderLink, error) {
+	query := `
+		SELECT id, readable_id, source_task_id, status, priority, title
+		FROM work_orders
+		WHERE parent_work_order_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []model.WorkOrderLink
+	for rows.Next() {
+		var l model.WorkOrderLink
+		if err := rows.Scan(&l.ID, &l.ReadableID, &l.SourceTaskID, &l.Status, &l.Priority, &l.Title); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+
+	return links, rows.Err()
+}
+
+// Update modifies a work order's details (v1.1 schema). The status is left alone:
+// it only changes through UpdateStatus, which enforces the lifecycle. Raising the
+// priority to Critical takes the asset Down in the same transaction, audited as
+// userID (empty for system updates).
+func (r *WorkOrderRepository) Update(ctx context.Context, wo *model.WorkOrder, userID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// A priority change re-derives the SLA deadlines from the creation time
 	query := `
-		INSERT INTO work_orders (tenant_id, asset_id, assigned_user_id, status, origin, priority, title, description)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, readable_id, created_at
+		UPDATE work_orders w
+		SET priority = $2, description = $3, title = $4,
+			respond_by = CASE WHEN w.priority = $2 THEN w.respond_by
+				ELSE ` + slaDeadlineExpr("w.created_at", "w.tenant_id", "$2", "respondHours") + ` END,
+			resolve_by = CASE WHEN w.priority = $2 THEN w.resolve_by
+				ELSE ` + slaDeadlineExpr("w.created_at", "w.tenant_id", "$2", "resolveHours") + ` END
+		FROM (SELECT id, priority FROM work_orders WHERE id = $1 FOR UPDATE) old
+		WHERE w.id = old.id
+		RETURNING old.priority, w.status, w.respond_by, w.resolve_by
 	`
 
-	return r.db.QueryRow(ctx, query,
-		wo.TenantID, wo.AssetID, wo.AssignedUserID, wo.Status, wo.Origin, wo.Priority, wo.Title, wo.Description,
-	).Scan(&wo.ID, &wo.ReadableID, &wo.CreatedAt)
+	var from string
+	err = tx.QueryRow(ctx, query, wo.ID, wo.Priority, wo.Description, wo.Title).
+		Scan(&from, &wo.Status, &wo.RespondBy, &wo.ResolveBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWorkOrderNotFound
+		}
+		return err
+	}
+
+	if err := takeAssetDownIfCritical(ctx, tx, wo, from, userID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-// Update modifies an existing work order (v1.1 schema)
-func (r *WorkOrderRepository) Update(ctx context.Context, wo *model.WorkOrder) error {
+// UpdateStatus moves a work order from its current status to the given status.
+// The update only applies if the stored status still matches wo.Status, so two
+// concurrent transitions cannot both succeed. started_at is stamped the first
+// time work starts and completed_at when work is completed (cleared on rework).
+// The close-out fields (failure codes, notes) are written from wo in the same update.
+// Closing a PM work order resets its schedule's baseline, and those of the schedules
+// it suppresses, in the same transaction, so a schedule only counts as performed
+// once the work has passed QC.
+func (r *WorkOrderRepository) UpdateStatus(ctx context.Context, wo *model.WorkOrder, status string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		UPDATE work_orders
-		SET status = $2, priority = $3, description = $4, title = $5
-		WHERE id = $1
+		SET status = $3,
+			started_at = CASE WHEN $3 = 'In_Progress' AND started_at IS NULL THEN NOW() ELSE started_at END,
+			completed_at = CASE
+				WHEN $3 = 'Work_Complete' THEN NOW()
+				WHEN $3 = 'In_Progress' THEN NULL
+				ELSE completed_at
+			END,
+			problem_code_id = $4, cause_code_id = $5, remedy_code_id = $6, closeout_notes = $7
+		WHERE id = $1 AND status = $2
+		RETURNING status, started_at, completed_at
 	`
 
-	_, err := r.db.Exec(ctx, query, wo.ID, wo.Status, wo.Priority, wo.Description, wo.Title)
-	return err
+	err = tx.QueryRow(ctx, query, wo.ID, wo.Status, status,
+		wo.ProblemCodeID, wo.CauseCodeID, wo.RemedyCodeID, wo.CloseoutNotes,
+	).Scan(&wo.Status, &wo.StartedAt, &wo.CompletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWorkOrderStatusConflict
+		}
+		return err
+	}
+
+	if status == model.WOStatusClosed && wo.PMScheduleID != nil {
+		if _, err := resetBaseline(ctx, tx, wo.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Assign sets or clears (nil) the assignee of a work order
+func (r *WorkOrderRepository) Assign(ctx context.Context, id string, userID *string) error {
+	result, err := r.db.Exec(ctx, `UPDATE work_orders SET assigned_user_id = $2 WHERE id = $1`, id, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWorkOrderNotFound
+	}
+	return nil
+}
+
+// Workload summarizes open work orders per active user of the given roles,
+// including users with nothing assigned, busiest first
+func (r *WorkOrderRepository) Workload(ctx context.Context, tenantID string, roles []string) ([]model.TechnicianWorkload, error) {
+	query := `
+		SELECT
+			u.id, COALESCE(u.full_name, u.email), u.role,
+			COUNT(w.id),
+			COUNT(w.id) FILTER (WHERE w.status = 'Requested'),
+			COUNT(w.id) FILTER (WHERE w.status = 'Approved'),
+			COUNT(w.id) FILTER (WHERE w.status = 'In_Progress'),
+			COUNT(w.id) FILTER (WHERE w.status = 'Work_Complete'),
+			COUNT(w.id) FILTER (WHERE w.priority = 'Critical'),
+			COUNT(w.id) FILTER (WHERE w.priority = 'High')
+		FROM users u
+		LEFT JOIN work_orders w ON w.assigned_user_id = u.id
+			AND w.status NOT IN ('Closed', 'Cancelled')
+		WHERE u.tenant_id = $1 AND u.is_active = TRUE AND u.role = ANY($2)
+		GROUP BY u.id, u.full_name, u.email, u.role
+		ORDER BY COUNT(w.id) DESC, u.full_name
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, roles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workload := []model.TechnicianWorkload{}
+	for rows.Next() {
+		var t model.TechnicianWorkload
+		err := rows.Scan(&t.UserID, &t.FullName, &t.Role,
+			&t.OpenTotal, &t.Requested, &t.Approved, &t.InProgress, &t.WorkComplete,
+			&t.Critical, &t.High)
+		if err != nil {
+			return nil, err
+		}
+		workload = append(workload, t)
+	}
+
+	return workload, rows.Err()
+}
+
+// CountUnassignedOpen counts open work orders nobody is assigned to
+func (r *WorkOrderRepository) CountUnassignedOpen(ctx context.Context, tenantID string) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM work_orders
+		WHERE tenant_id = $1 AND assigned_user_id IS NULL AND status NOT IN ('Closed', 'Cancelled')
+	`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, tenantID).Scan(&count)
+	return count, err
+}
+
+// ListSLABreaches retrieves open work orders (optionally for one tenant) that have
+// breached an SLA deadline not yet escalated, oldest first
+func (r *WorkOrderRepository) ListSLABreaches(ctx context.Context, tenantID string) ([]model.WorkOrder, error) {
+	query := `
+		SELECT ` + workOrderColumns + `
+		FROM work_orders w
+		LEFT JOIN assets a ON w.asset_id = a.id
+		WHERE ($1 = '' OR w.tenant_id::text = $1)
+			AND ((` + slaResponseBreachedExpr + ` AND w.escalation_level < 1)
+				OR (` + slaResolutionBreachedExpr + ` AND w.escalation_level < 2))
+		ORDER BY w.created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workOrders []model.WorkOrder
+	for rows.Next() {
+		var wo model.WorkOrder
+		if err := scanWorkOrder(rows, &wo); err != nil {
+			return nil, err
+		}
+		workOrders = append(workOrders, wo)
+	}
+
+	return workOrders, rows.Err()
+}
+
+// Escalate raises a work order's priority to wo.Priority and its escalation level.
+// It only applies while the stored level is below the new one, so a breach
+// escalates once; the result reports whether it applied. Escalating to Critical
+// takes the asset Down in the same transaction, as creating a Critical work order does;
+// the escalation sweep is not a user, so that is audited as a system event.
+func (r *WorkOrderRepository) Escalate(ctx context.Context, wo *model.WorkOrder, level int) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE work_orders w
+		SET priority = $2, escalation_level = $3, escalated_at = NOW()
+		FROM (SELECT id, priority FROM work_orders WHERE id = $1 FOR UPDATE) old
+		WHERE w.id = old.id AND w.escalation_level < $3
+		RETURNING old.priority
+	`
+
+	var from string
+	err = tx.QueryRow(ctx, query, wo.ID, wo.Priority, level).Scan(&from)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := takeAssetDownIfCritical(ctx, tx, wo, from, ""); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// takeAssetDownIfCritical takes the work order's asset Down when its priority has
+// just become Critical (from is the previous priority) and the work is still open.
+// userID is the user who raised the priority, empty for system changes.
+func takeAssetDownIfCritical(ctx context.Context, tx pgx.Tx, wo *model.WorkOrder, from, userID string) error {
+	if wo.Priority != model.WOPriorityCritical || from == model.WOPriorityCritical || wo.AssetID == nil {
+		return nil
+	}
+	if wo.Status == model.WOStatusClosed || wo.Status == model.WOStatusCancelled {
+		return nil
+	}
+	return takeAssetDown(ctx, tx, wo, userID)
+}
+
+// CancelSuppressed cancels a work order that has not started yet and appends the
+// reason to its description. It reports false if the work order was already underway.
+func (r *WorkOrderRepository) CancelSuppressed(ctx context.Context, id, reason string) (bool, error) {
+	query := `
+		UPDATE work_orders
+		SET status = 'Cancelled',
+			description = COALESCE(description || E'\n\n', '') || $2
+		WHERE id = $1 AND status IN ('Requested', 'Approved')
+	`
+
+	result, err := r.db.Exec(ctx, query, id, reason)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
 }
 
-// UpdateStatus changes a work order's status
-func (r *WorkOrderRepository) UpdateStatus(ctx context.Context, id, status string) error {
-	query := `UPDATE work_orders SET status = $2 WHERE id = $1`
-	_, err := r.db.Exec(ctx, query, id, status)
+// AddSuppressedPMs records further PM schedules an open preventive work order
+// covers, so their baselines also reset when it closes. Schedules it already
+// covers are not repeated; closed and cancelled work orders are left alone.
+func (r *WorkOrderRepository) AddSuppressedPMs(ctx context.Context, id string, pmIDs []string) error {
+	query := `
+		UPDATE work_orders w
+		SET suppressed_pm_ids = w.suppressed_pm_ids || COALESCE((
+			SELECT jsonb_agg(pm_id) FROM unnest($2::text[]) pm_id
+			WHERE NOT w.suppressed_pm_ids @> to_jsonb(pm_id)
+		), '[]'::jsonb)
+		WHERE w.id = $1
+			AND w.status NOT IN ('Closed', 'Cancelled')
+			AND NOT w.suppressed_pm_ids @> to_jsonb($2::text[])
+	`
+
+	_, err := r.db.Exec(ctx, query, id, pmIDs)
 	return err
 }
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -8,6 +8,7 @@ import (
 	"ioi-amms/internal/config"
 	"ioi-amms/internal/database"
 	"ioi-amms/internal/handler"
+	"ioi-amms/internal/mail"
 	"ioi-amms/internal/middleware"
 	"ioi-amms/internal/repository"
 	"ioi-amms/internal/service"
@@ -40,11 +41,34 @@ func NewRouter(db database.Service, cfg *config.Config) http.Handler {
 	auditRepo := repository.NewAuditRepository(db.Pool())
 	inventoryRepo := repository.NewInventoryRepository(db.Pool())
 	analyticsRepo := repository.NewAnalyticsRepository(db.Pool()) // [NEW]
+	pmRepo := repository.NewPMRepository(db.Pool())
+	checklistRepo := repository.NewChecklistRepository(db.Pool())
+	taskRepo := repository.NewTaskRepository(db.Pool())
+	laborRepo := repository.NewLaborRepository(db.Pool())
+	failureCodeRepo := repository.NewFailureCodeRepository(db.Pool())
+	commentRepo := repository.NewCommentRepository(db.Pool())
+	timelineRepo := repository.NewTimelineRepository(db.Pool())
+	notificationRepo := repository.NewNotificationRepository(db.Pool())
+	downtimeRepo := repository.NewDowntimeRepository(db.Pool())
+	briefRepo := repository.NewBriefRepository(db.Pool())
+	dashboardRepo := repository.NewDashboardRepository(db.Pool())
+	movementRepo := repository.NewMovementRepository(db.Pool())
 
 	// Initialize services
 	// Initialize services
 	// Initialize services
 	auditService := service.NewAuditService(auditRepo)
+	assetService := service.NewAssetService(assetRepo, woRepo, auditService)
+	movementService := service.NewMovementService(movementRepo, tenantRepo, auditService)
+	woService := service.NewWorkOrderService(woRepo, taskRepo, laborRepo, failureCodeRepo, userRepo, downtimeRepo, tenantRepo, auditService)
+	taskService := service.NewTaskService(taskRepo, woRepo, auditService)
+	laborService := service.NewLaborService(laborRepo, woRepo, userRepo, auditService)
+	inventoryService := service.NewInventoryService(inventoryRepo, woRepo)
+	pmService := service.NewPMService(pmRepo, woRepo, auditService)
+	commentService := service.NewCommentService(commentRepo, timelineRepo, woRepo, userRepo, auditService)
+	briefService := service.NewBriefService(briefRepo, analyticsRepo, pmRepo, inventoryRepo, tenantRepo,
+		mail.NewSender(cfg.Mail), cfg.Scheduler.BriefSendHour)
+	dashboardService := service.NewDashboardService(dashboardRepo, analyticsRepo, woRepo, pmRepo, inventoryRepo)
 
 	// Initialize storage service (moved up for dependency)
 	var fileHandler *handler.FileHandler
@@ -56,14 +80,26 @@ func NewRouter(db database.Service, cfg *config.Config) http.Handler {
 	}
 
 	// Initialize handlers
-	assetHandler := handler.NewAssetHandler(assetRepo, auditService)
-	woHandler := handler.NewWorkOrderHandler(woRepo)
+	assetHandler := handler.NewAssetHandler(assetRepo, assetService)
+	movementHandler := handler.NewMovementHandler(movementRepo, movementService)
+	woHandler := handler.NewWorkOrderHandler(woRepo, checklistRepo, woService)
 	locationHandler := handler.NewLocationHandler(locationRepo)
 	userHandler := handler.NewUserHandler(userRepo)
 	tenantHandler := handler.NewTenantHandler(tenantRepo)
 	auditHandler := handler.NewAuditHandler(auditRepo)
 	inventoryHandler := handler.NewInventoryHandler(inventoryRepo)
-	analyticsHandler := handler.NewAnalyticsHandler(analyticsRepo)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsRepo, pmRepo)
+	pmHandler := handler.NewPMHandler(pmRepo, assetRepo, checklistRepo, pmService)
+	checklistHandler := handler.NewChecklistHandler(checklistRepo)
+	failureCodeHandler := handler.NewFailureCodeHandler(failureCodeRepo)
+	taskHandler := handler.NewTaskHandler(taskService)
+	laborHandler := handler.NewLaborHandler(laborService)
+	resourceHandler := handler.NewResourceHandler(inventoryService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	notificationHandler := handler.NewNotificationHandler(notificationRepo)
+	downtimeHandler := handler.NewDowntimeHandler(downtimeRepo, assetRepo)
+	briefHandler := handler.NewBriefHandler(briefRepo, briefService)
+	dashboardHandler := handler.NewDashboardHandler(dashboardService)
 	systemHandler := SystemHealthHandler(db, storageService)
 
 	// API v1 routes
@@ -78,9 +114,27 @@ func NewRouter(db database.Service, cfg *config.Config) http.Handler {
 
 			// Asset routes
 			assetHandler.RegisterRoutes(r)
+			downtimeHandler.RegisterRoutes(r)
+			movementHandler.RegisterRoutes(r)
 
 			// Work Order routes
 			woHandler.RegisterRoutes(r)
+			taskHandler.RegisterRoutes(r)
+			laborHandler.RegisterRoutes(r)
+			resourceHandler.RegisterRoutes(r)
+			commentHandler.RegisterRoutes(r)
+
+			// In-app notifications
+			notificationHandler.RegisterRoutes(r)
+
+			// Preventive Maintenance schedules
+			pmHandler.RegisterRoutes(r)
+
+			// Checklist templates
+			checklistHandler.RegisterRoutes(r)
+
+			// Failure code catalog
+			failureCodeHandler.RegisterRoutes(r)
 
 			// Location routes
 			locationHandler.RegisterRoutes(r)
@@ -94,6 +148,12 @@ func NewRouter(db database.Service, cfg *config.Config) http.Handler {
 			// Analytics Dashboard (New)
 			analyticsHandler.RegisterRoutes(r)
 
+			// Monday Morning Brief
+			briefHandler.RegisterRoutes(r)
+
+			// Configurable KPI dashboards
+			dashboardHandler.RegisterRoutes(r)
+
 			// File routes (if storage available)
 			if fileHandler != nil {
 				fileHandler.RegisterRoutes(r)
@@ -12,21 +12,52 @@ import (
 
 	"ioi-amms/internal/config"
 	"ioi-amms/internal/database"
+	"ioi-amms/internal/mail"
+	"ioi-amms/internal/repository"
 	"ioi-amms/internal/routes"
+	"ioi-amms/internal/service"
 )
 
 type Server struct {
-	config *config.Config
-	db     database.Service
-	http   *http.Server
+	config   *config.Config
+	db       database.Service
+	http     *http.Server
+	pm       *service.PMService
+	sla      *service.SLAService
+	brief    *service.BriefService
+	snapshot *service.SnapshotService
 }
 
 func NewServer(cfg *config.Config) *Server {
 	db := database.NewWithConfig(cfg)
+	audit := service.NewAuditService(repository.NewAuditRepository(db.Pool()))
+	woRepo := repository.NewWorkOrderRepository(db.Pool())
+	pmRepo := repository.NewPMRepository(db.Pool())
+	analyticsRepo := repository.NewAnalyticsRepository(db.Pool())
 
 	server := &Server{
 		config: cfg,
 		db:     db,
+		pm: service.NewPMService(
+			pmRepo,
+			woRepo,
+			audit,
+		),
+		sla: service.NewSLAService(
+			woRepo,
+			repository.NewNotificationRepository(db.Pool()),
+			audit,
+		),
+		brief: service.NewBriefService(
+			repository.NewBriefRepository(db.Pool()),
+			analyticsRepo,
+			pmRepo,
+			repository.NewInventoryRepository(db.Pool()),
+			repository.NewTenantRepository(db.Pool()),
+			mail.NewSender(cfg.Mail),
+			cfg.Scheduler.BriefSendHour,
+		),
+		snapshot: service.NewSnapshotService(analyticsRepo),
 		http: &http.Server{
 			Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
 			Handler:      routes.NewRouter(db, cfg),
@@ -48,6 +79,14 @@ func (s *Server) Start() error {
 	return s.http.ListenAndServe()
 }
 
+// StartJobs launches background jobs; they stop when ctx is cancelled
+func (s *Server) StartJobs(ctx context.Context) {
+	go s.pm.Run(ctx, s.config.Scheduler.PMInterval)
+	go s.sla.Run(ctx, s.config.Scheduler.SLAInterval)
+	go s.brief.Run(ctx, s.config.Scheduler.BriefInterval)
+	go s.snapshot.Run(ctx, s.config.Scheduler.SnapshotInterval)
+}
+
 // Shutdown gracefully stops the server
 func (s *Server) Shutdown(ctx context.Context) error {
 	slog.Info("Shutting down server...")
@@ -65,6 +104,11 @@ func (s *Server) Shutdown(ctx context.Context) error {
 func Run(cfg *config.Config) error {
 	server := NewServer(cfg)
 
+	// Background jobs run until shutdown begins
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	server.StartJobs(jobsCtx)
+
 	// Channel to listen for shutdown signals
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
@@ -80,6 +124,7 @@ func Run(cfg *config.Config) error {
 	// Wait for shutdown signal
 	<-stop
 	slog.Info("Received shutdown signal")
+	stopJobs()
 
 	// Create shutdown context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"ioi-amms/internal/auth"
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+var (
+	ErrInvalidAssetStatus     = errors.New("unknown asset status")
+	ErrAssetTransition        = errors.New("asset status transition not allowed")
+	ErrAssetHasOpenWorkOrders = errors.New("asset has open work orders")
+	ErrAssetNotVerified       = errors.New("asset must be field verified before activation")
+	ErrAssetForbidden         = errors.New("asset belongs to another tenant")
+)
+
+// assetVerifyPermission lets Supervisors and above verify field assets, and
+// activate them without a prior verification
+const assetVerifyPermission = middleware.PermissionAssetWrite
+
+// AssetTransitionError carries the context of a rejected asset status change
+type AssetTransitionError struct {
+	Err            error
+	From           string
+	To             string
+	Allowed        []string
+	OpenWorkOrders int
+}
+
+func (e *AssetTransitionError) Error() string {
+	return e.Err.Error() + ": " + e.From + " -> " + e.To
+}
+
+func (e *AssetTransitionError) Unwrap() error {
+	return e.Err
+}
+
+// AssetService guards the asset status lifecycle
+type AssetService struct {
+	repo   *repository.AssetRepository
+	woRepo *repository.WorkOrderRepository
+	audit  *AuditService
+}
+
+// NewAssetService creates a new asset service
+func NewAssetService(repo *repository.AssetRepository, woRepo *repository.WorkOrderRepository, audit *AuditService) *AssetService {
+	return &AssetService{repo: repo, woRepo: woRepo, audit: audit}
+}
+
+// Create registers a new asset. Assets may start in any status but Archived; an
+// Active field asset must be registered by a Supervisor or above, since it cannot
+// have been verified yet.
+func (s *AssetService) Create(ctx context.Context, claims *auth.Claims, asset *model.Asset) error {
+	if !model.IsValidAssetStatus(asset.Status) {
+		return ErrInvalidAssetStatus
+	}
+	if asset.Status == model.AssetStatusArchived {
+		return &AssetTransitionError{
+			Err: ErrAssetTransition, From: model.AssetStatusDraft, To: asset.Status,
+			Allowed: model.AssetTransitions[model.AssetStatusDraft],
+		}
+	}
+	if err := s.checkActivation(claims, asset, model.AssetStatusDraft, asset.Status); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, asset); err != nil {
+		return err
+	}
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionCreate, model.AuditEntityAsset, asset.ID, map[string]interface{}{
+		"name":   asset.Name,
+		"status": asset.Status,
+	})
+	return nil
+}
+
+// Update saves changes to an asset that was read with status from. A status change
+// must follow the lifecycle and its preconditions, and is audited separately from
+// other edits.
+func (s *AssetService) Update(ctx context.Context, claims *auth.Claims, asset *model.Asset, from string, changed []string) error {
+	if asset.TenantID != claims.TenantID {
+		return ErrAssetForbidden
+	}
+
+	if asset.Status != from {
+		if err := s.CheckTransition(ctx, claims, asset, from, asset.Status); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.Update(ctx, asset, from, claims.UserID); err != nil {
+		if errors.Is(err, repository.ErrAssetOpenWorkOrders) {
+			return s.openWorkOrdersError(ctx, asset, from)
+		}
+		return err
+	}
+
+	if asset.Status != from {
+		changes := map[string]interface{}{"from": from, "to": asset.Status}
+		if from == model.AssetStatusDraft && asset.Status == model.AssetStatusActive && needsVerification(asset) {
+			changes["verificationOverride"] = true
+		}
+		s.audit.Log(ctx, claims.UserID, model.AuditActionStatusChange, model.AuditEntityAsset, asset.ID, changes)
+	}
+	if len(changed) > 0 {
+		s.audit.Log(ctx, claims.UserID, model.AuditActionUpdate, model.AuditEntityAsset, asset.ID, map[string]interface{}{
+			"fields": changed,
+		})
+	}
+	return nil
+}
+
+// Move relocates an asset and everything below it to a location, returning the
+// shipment reference of the recorded movements and the moved assets. Assets
+// already at the location are left alone; when nothing moves the reference is empty.
+func (s *AssetService) Move(ctx context.Context, claims *auth.Claims, id, toLocationID string) (string, []model.Asset, error) {
+	ref, moved, err := s.repo.Move(ctx, claims.TenantID, id, toLocationID, claims.UserID)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(moved) == 0 {
+		return "", moved, nil
+	}
+
+	ids := make([]string, len(moved))
+	for i, a := range moved {
+		ids[i] = a.ID
+	}
+	s.audit.Log(ctx, claims.UserID, model.AuditActionMove, model.AuditEntityAsset, id, map[string]interface{}{
+		"toLocationId": toLocationID,
+		"shipmentRef":  ref,
+		"movedAssets":  ids,
+	})
+	return ref, moved, nil
+}
+
+// CheckTransition reports whether the asset may move from one status to another:
+// the move must be in the lifecycle and a field asset is only activated once
+// verified (Supervisors and above may activate it regardless). That an archived
+// asset has no open work orders is checked by the repository when saving.
+func (s *AssetService) CheckTransition(ctx context.Context, claims *auth.Claims, asset *model.Asset, from, to string) error {
+	if !model.IsValidAssetStatus(to) {
+		return ErrInvalidAssetStatus
+	}
+	if !model.CanTransitionAsset(from, to) {
+		return &AssetTransitionError{Err: ErrAssetTransition, From: from, To: to, Allowed: model.AssetTransitions[from]}
+	}
+	return s.checkActivation(claims, asset, from, to)
+}
+
+// openWorkOrdersError describes a refused archive with the asset's open work order count
+func (s *AssetService) openWorkOrdersError(ctx context.Context, asset *model.Asset, from string) error {
+	open, err := s.woRepo.List(ctx, model.WorkOrderListParams{
+		TenantID: asset.TenantID,
+		AssetID:  asset.ID,
+		OpenOnly: true,
+		Page:     1,
+		Limit:    1,
+	})
+	if err != nil {
+		return err
+	}
+	return &AssetTransitionError{
+		Err: ErrAssetHasOpenWorkOrders, From: from, To: asset.Status,
+		Allowed: model.AssetTransitions[from], OpenWorkOrders: open.Total,
+	}
+}
+
+// checkActivation enforces field verification on Draft -> Active
+func (s *AssetService) checkActivation(claims *auth.Claims, asset *model.Asset, from, to string) error {
+	if from != model.AssetStatusDraft || to != model.AssetStatusActive || !needsVerification(asset) {
+		return nil
+	}
+	if middleware.HasPermission(claims.Role, assetVerifyPermission) {
+		return nil
+	}
+	return &AssetTransitionError{Err: ErrAssetNotVerified, From: from, To: to, Allowed: model.AssetTransitions[from]}
+}
+
+// CanVerifyAsset reports whether the role may set an asset's field verification
+func CanVerifyAsset(role string) bool {
+	return middleware.HasPermission(role, assetVerifyPermission)
+}
+
+// needsVerification reports whether the asset is a field asset not yet verified
+func needsVerification(asset *model.Asset) bool {
+	return asset.IsFieldRelated && !asset.IsFieldVerified
+}
@@ -20,6 +20,27 @@ func NewAuditService(repo *repository.AuditRepository) *AuditService {
 
 // Log records an audit event asynchronously
 func (s *AuditService) Log(ctx context.Context, userID, action, entityType, entityID string, changes map[string]interface{}) {
+	s.write(&model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+	})
+}
+
+// LogSystem records an audit event raised by the system rather than a user
+func (s *AuditService) LogSystem(ctx context.Context, tenantID, action, entityType, entityID string, changes map[string]interface{}) {
+	s.write(&model.AuditLog{
+		TenantID:   tenantID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+	})
+}
+
+func (s *AuditService) write(log *model.AuditLog) {
 	// Use a detached context for async logging to ensure it completes even if request cancels
 	// However, we need to be careful with detached contexts in Go < 1.21.
 	// In Go 1.21+, context.WithoutCancel(ctx).
@@ -30,18 +51,10 @@ func (s *AuditService) Log(ctx context.Context, userID, action, entityType, enti
 		// Or better, inherit values but not cancellation?
 		// For simplicity, just use Background.
 
-		log := &model.AuditLog{
-			UserID:     userID,
-			Action:     action,
-			EntityType: entityType,
-			EntityID:   entityID,
-			Changes:    changes,
-		}
-
 		if err := s.repo.Create(logCtx, log); err != nil {
 			slog.Error("Failed to write audit log",
-				slog.String("action", action),
-				slog.String("entity", entityType),
+				slog.String("action", log.Action),
+				slog.String("entity", log.EntityType),
 				slog.String("error", err.Error()),
 			)
 		}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ioi-amms/internal/mail"
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/report"
+	"ioi-amms/internal/repository"
+)
+
+// Brief list sizes
+const (
+	briefCompletedLimit = 20
+	briefLowStockLimit  = 25
+)
+
+// BriefService builds the Monday Morning Brief and emails it to subscribers
+type BriefService struct {
+	briefs    briefStore
+	analytics briefAnalytics
+	pmRepo    briefSchedules
+	inventory briefStock
+	tenants   briefTenants
+	sender    mail.Sender
+	sendHour  int
+}
+
+// briefStore is the subscription and activity data the brief service uses
+type briefStore interface {
+	ListPending(ctx context.Context, weekStart time.Time) ([]model.BriefSubscriber, error)
+	RecordDelivery(ctx context.Context, tenantID, userID string, weekStart time.Time) error
+	WorkOrderActivity(ctx context.Context, tenantID string, from, to time.Time, limit int) (int, int, []model.WorkOrderLink, error)
+}
+
+// briefAnalytics is the reporting data the brief summarizes
+type briefAnalytics interface {
+	GetDashboardStats(ctx context.Context, tenantID string) (*model.DashboardStats, error)
+	ListCriticalDown(ctx context.Context, tenantID string) ([]model.CriticalDownAsset, error)
+	GetReliability(ctx context.Context, params model.ReliabilityParams) ([]model.ReliabilityMetrics, error)
+}
+
+// briefSchedules lists the PM schedules checked for overdue services
+type briefSchedules interface {
+	ListForEvaluation(ctx context.Context, tenantID string) ([]model.PMSchedule, error)
+}
+
+// briefStock lists the low stock shown in the brief
+type briefStock interface {
+	ListStock(ctx context.Context, params model.InventoryStockListParams) (*model.PaginatedResult[model.InventoryStock], error)
+}
+
+// briefTenants names the tenant a brief is for
+type briefTenants interface {
+	GetSettings(ctx context.Context, id string) (*repository.Tenant, error)
+}
+
+// NewBriefService creates a new brief service. Briefs for the previous week are
+// sent from sendHour (UTC) on Monday.
+func NewBriefService(briefs *repository.BriefRepository, analytics *repository.AnalyticsRepository, pmRepo *repository.PMRepository, inventory *repository.InventoryRepository, tenants *repository.TenantRepository, sender mail.Sender, sendHour int) *BriefService {
+	return &BriefService{
+		briefs: briefs, analytics: analytics, pmRepo: pmRepo, inventory: inventory, tenants: tenants,
+		sender: sender, sendHour: sendHour,
+	}
+}
+
+// Build gathers the brief for the tenant and the week starting weekStart
+func (s *BriefService) Build(ctx context.Context, tenantID string, weekStart time.Time) (*model.WeeklyBrief, error) {
+	tenant, err := s.tenants.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	b := &model.WeeklyBrief{
+		TenantID:    tenantID,
+		TenantName:  tenant.Name,
+		WeekStart:   weekStart,
+		WeekEnd:     weekStart.AddDate(0, 0, 7),
+		GeneratedAt: now,
+	}
+
+	b.Opened, b.Completed, b.CompletedWorkOrders, err = s.briefs.WorkOrderActivity(ctx, tenantID, b.WeekStart, b.WeekEnd, briefCompletedLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.analytics.GetDashboardStats(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.pmRepo.ListForEvaluation(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	b.OverduePMs = model.OverdueSchedules(schedules, now)
+	stats.OverduePMs = len(b.OverduePMs)
+	b.Stats = *stats
+
+	if b.CriticalAssets, err = s.analytics.ListCriticalDown(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	lowStock, err := s.inventory.ListStock(ctx, model.InventoryStockListParams{
+		TenantID: tenantID,
+		LowStock: true,
+		Page:     1,
+		Limit:    briefLowStockLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.LowStock, b.LowStockTotal = lowStock.Data, lowStock.Total
+
+	groups, err := s.analytics.GetReliability(ctx, model.ReliabilityParams{
+		TenantID: tenantID,
+		GroupBy:  model.ReliabilityByAsset,
+		From:     b.WeekStart,
+		To:       b.WeekEnd,
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.Reliability = model.ReliabilityMetrics{Key: "all", Label: "All assets"}
+	for _, g := range groups {
+		b.Reliability.Add(g)
+	}
+	b.Reliability.Compute()
+
+	return b, nil
+}
+
+// Render builds the brief and renders it as a PDF addressed to recipient
+func (s *BriefService) Render(ctx context.Context, tenantID string, weekStart time.Time, recipient string) ([]byte, error) {
+	b, err := s.Build(ctx, tenantID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	return report.BriefPDF(b, recipient)
+}
+
+// Run sends due briefs every interval until ctx is cancelled. A non-positive
+// interval disables delivery.
+func (s *BriefService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		slog.Info("Weekly brief delivery disabled")
+		return
+	}
+
+	slog.Info("Weekly brief delivery started", slog.Duration("interval", interval), slog.Int("sendHour", s.sendHour))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := s.SendDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Weekly brief delivery failed", slog.String("error", err.Error()))
+		} else if sent > 0 {
+			slog.Info("Weekly briefs sent", slog.Int("count", sent))
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Weekly brief delivery stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue emails last week's brief to every subscriber who has not received it,
+// once the current week has reached Monday's send hour. Each tenant's brief is
+// built once; a failed delivery is retried on the next run.
+func (s *BriefService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	thisWeek := model.BriefWeekStart(now)
+	if now.Before(thisWeek.Add(time.Duration(s.sendHour) * time.Hour)) {
+		return 0, nil
+	}
+	weekStart := thisWeek.AddDate(0, 0, -7)
+
+	subscribers, err := s.briefs.ListPending(ctx, weekStart)
+	if err != nil {
+		return 0, err
+	}
+
+	briefs := map[string]*model.WeeklyBrief{}
+	sent := 0
+	for _, sub := range subscribers {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+		// The brief shows tenant-wide reports; users who lost access no longer get it
+		if !middleware.HasPermission(sub.Role, middleware.PermissionReportView) {
+			continue
+		}
+
+		b, ok := briefs[sub.TenantID]
+		if !ok {
+			b, err = s.Build(ctx, sub.TenantID, weekStart)
+			if err != nil {
+				slog.Error("Failed to build weekly brief",
+					slog.String("tenantId", sub.TenantID), slog.String("error", err.Error()))
+				briefs[sub.TenantID] = nil
+				continue
+			}
+			briefs[sub.TenantID] = b
+		}
+		if b == nil {
+			continue
+		}
+
+		if err := s.deliver(ctx, b, sub); err != nil {
+			slog.Error("Failed to send weekly brief",
+				slog.String("userId", sub.UserID), slog.String("error", err.Error()))
+			continue
+		}
+		if err := s.briefs.RecordDelivery(ctx, sub.TenantID, sub.UserID, weekStart); err != nil {
+			slog.Error("Failed to record weekly brief delivery",
+				slog.String("userId", sub.UserID), slog.String("error", err.Error()))
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// deliver renders the brief for one subscriber and emails it
+func (s *BriefService) deliver(ctx context.Context, b *model.WeeklyBrief, sub model.BriefSubscriber) error {
+	pdf, err := report.BriefPDF(b, sub.FullName)
+	if err != nil {
+		return err
+	}
+
+	week := b.WeekStart.Format("2 Jan 2006")
+	body := fmt.Sprintf(
+		"Hello %s,\n\nAttached is the Monday Morning Brief for %s, week of %s.\n\n"+
+			"Last week: %d work orders opened, %d completed.\n"+
+			"Now: %d open work orders, %d critical assets down, %d overdue PMs, %d low stock items.\n\n"+
+			"You are receiving this because you subscribed to the weekly brief.\n",
+		sub.FullName, b.TenantName, week,
+		b.Opened, b.Completed,
+		b.Stats.OpenWorkOrders, len(b.CriticalAssets), len(b.OverduePMs), b.LowStockTotal,
+	)
+
+	return s.sender.Send(ctx, mail.Message{
+		To:      []string{sub.Email},
+		Subject: fmt.Sprintf("Monday Morning Brief - %s - week of %s", b.TenantName, week),
+		Body:    body,
+		Attachments: []mail.Attachment{{
+			Filename:    BriefFilename(b.WeekStart),
+			ContentType: "application/pdf",
+			Data:        pdf,
+		}},
+	})
+}
+
+// BriefFilename names the brief PDF for the week starting weekStart
+func BriefFilename(weekStart time.Time) string {
+	return fmt.Sprintf("monday-brief_%s.pdf", weekStart.Format("2006-01-02"))
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"ioi-amms/internal/mail"
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+// fakeBriefStore serves pending subscribers and records deliveries in memory
+type fakeBriefStore struct {
+	pending     []model.BriefSubscriber
+	listed      int
+	failRecord  string // user whose delivery cannot be recorded
+	recorded    []string
+	recordedFor time.Time
+}
+
+func (f *fakeBriefStore) ListPending(ctx context.Context, weekStart time.Time) ([]model.BriefSubscriber, error) {
+	f.listed++
+	return f.pending, nil
+}
+
+func (f *fakeBriefStore) RecordDelivery(ctx context.Context, tenantID, userID string, weekStart time.Time) error {
+	if userID == f.failRecord {
+		return errors.New("record failed")
+	}
+	f.recorded = append(f.recorded, userID)
+	f.recordedFor = weekStart
+	return nil
+}
+
+func (f *fakeBriefStore) WorkOrderActivity(ctx context.Context, tenantID string, from, to time.Time, limit int) (int, int, []model.WorkOrderLink, error) {
+	return 0, 0, nil, nil
+}
+
+// fakeBriefData serves an empty week of reporting data for every tenant except
+// "broken", and counts the briefs built per tenant
+type fakeBriefData struct {
+	builds map[string]int
+}
+
+func (f *fakeBriefData) GetSettings(ctx context.Context, id string) (*repository.Tenant, error) {
+	f.builds[id]++
+	if id == "broken" {
+		return nil, errors.New("query failed")
+	}
+	return &repository.Tenant{ID: id, Name: "Site " + id}, nil
+}
+
+func (f *fakeBriefData) GetDashboardStats(ctx context.Context, tenantID string) (*model.DashboardStats, error) {
+	return &model.DashboardStats{}, nil
+}
+
+func (f *fakeBriefData) ListCriticalDown(ctx context.Context, tenantID string) ([]model.CriticalDownAsset, error) {
+	return nil, nil
+}
+
+func (f *fakeBriefData) GetReliability(ctx context.Context, params model.ReliabilityParams) ([]model.ReliabilityMetrics, error) {
+	return nil, nil
+}
+
+func (f *fakeBriefData) ListForEvaluation(ctx context.Context, tenantID string) ([]model.PMSchedule, error) {
+	return nil, nil
+}
+
+func (f *fakeBriefData) ListStock(ctx context.Context, params model.InventoryStockListParams) (*model.PaginatedResult[model.InventoryStock], error) {
+	return &model.PaginatedResult[model.InventoryStock]{}, nil
+}
+
+// fakeSender collects messages instead of sending them
+type fakeSender struct {
+	fail string // recipient whose delivery fails
+	sent []mail.Message
+}
+
+func (f *fakeSender) Send(ctx context.Context, msg mail.Message) error {
+	if len(msg.To) == 1 && msg.To[0] == f.fail {
+		return errors.New("mailbox unavailable")
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestSendDue(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	lastWeek := monday.AddDate(0, 0, -7)
+
+	sub := func(user, tenant, role string) model.BriefSubscriber {
+		return model.BriefSubscriber{UserID: user, TenantID: tenant, Email: user + "@example.com", FullName: strings.ToUpper(user), Role: role}
+	}
+	pending := []model.BriefSubscriber{
+		sub("ana", "plant", middleware.RoleSupervisor),
+		sub("ben", "plant", middleware.RoleManager),
+		sub("cy", "plant", middleware.RoleTechnician), // no report:view
+		sub("dee", "broken", middleware.RoleAdmin),    // brief cannot be built
+		sub("eve", "depot", middleware.RoleAdmin),     // mail bounces
+		sub("fay", "depot", middleware.RoleManager),   // delivery cannot be recorded
+		sub("gus", "depot", middleware.RoleSupervisor),
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		sent     int
+		mailed   []string
+		recorded []string
+		builds   map[string]int
+	}{
+		{
+			name: "before send hour",
+			now:  monday.Add(6*time.Hour + 59*time.Minute),
+		},
+		{
+			name:     "at send hour",
+			now:      monday.Add(7 * time.Hour),
+			sent:     3,
+			mailed:   []string{"ana@example.com", "ben@example.com", "fay@example.com", "gus@example.com"},
+			recorded: []string{"ana", "ben", "gus"},
+			builds:   map[string]int{"plant": 1, "broken": 1, "depot": 1},
+		},
+		{
+			name:     "later in the week",
+			now:      monday.AddDate(0, 0, 3),
+			sent:     3,
+			mailed:   []string{"ana@example.com", "ben@example.com", "fay@example.com", "gus@example.com"},
+			recorded: []string{"ana", "ben", "gus"},
+			builds:   map[string]int{"plant": 1, "broken": 1, "depot": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		store := &fakeBriefStore{pending: pending, failRecord: "fay"}
+		sender := &fakeSender{fail: "eve@example.com"}
+		data := &fakeBriefData{builds: map[string]int{}}
+		s := &BriefService{
+			briefs: store, analytics: data, pmRepo: data, inventory: data, tenants: data,
+			sender: sender, sendHour: 7,
+		}
+
+		sent, err := s.SendDue(context.Background(), tt.now)
+		if err != nil {
+			t.Fatalf("%s: SendDue: %v", tt.name, err)
+		}
+		if sent != tt.sent {
+			t.Errorf("%s: sent = %d, want %d", tt.name, sent, tt.sent)
+		}
+		if tt.sent == 0 {
+			if store.listed != 0 || len(sender.sent) != 0 {
+				t.Errorf("%s: briefs were sent before the send hour", tt.name)
+			}
+			continue
+		}
+
+		var mailed []string
+		for _, m := range sender.sent {
+			mailed = append(mailed, m.To...)
+		}
+		if strings.Join(mailed, ",") != strings.Join(tt.mailed, ",") {
+			t.Errorf("%s: mailed %v, want %v", tt.name, mailed, tt.mailed)
+		}
+		if strings.Join(store.recorded, ",") != strings.Join(tt.recorded, ",") {
+			t.Errorf("%s: recorded %v, want %v", tt.name, store.recorded, tt.recorded)
+		}
+		if !store.recordedFor.Equal(lastWeek) {
+			t.Errorf("%s: delivery recorded for week of %s, want %s", tt.name, store.recordedFor, lastWeek)
+		}
+		for tenant, want := range tt.builds {
+			if data.builds[tenant] != want {
+				t.Errorf("%s: %s brief built %d times, want %d", tt.name, tenant, data.builds[tenant], want)
+			}
+		}
+
+		for _, m := range sender.sent {
+			if !strings.Contains(m.Subject, "week of 5 Oct 2026") {
+				t.Errorf("%s: subject %q does not name the week", tt.name, m.Subject)
+			}
+			if len(m.Attachments) != 1 {
+				t.Errorf("%s: %d attachments, want 1", tt.name, len(m.Attachments))
+				continue
+			}
+			a := m.Attachments[0]
+			if a.Filename != BriefFilename(lastWeek) || a.ContentType != "application/pdf" || !bytes.HasPrefix(a.Data, []byte("%PDF")) {
+				t.Errorf("%s: attachment %s (%s) is not the brief PDF", tt.name, a.Filename, a.ContentType)
+			}
+		}
+	}
+}
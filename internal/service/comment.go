@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"ioi-amms/internal/auth"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+// maxCommentLength bounds a comment body, in characters
+const maxCommentLength = 4000
+
+var (
+	ErrCommentEmpty          = errors.New("comment body is required")
+	ErrCommentTooLong        = errors.New("comment body is too long")
+	ErrCommentInvalidParent  = errors.New("parent comment must belong to the same work order")
+	ErrCommentInvalidMention = errors.New("mentioned users must be active users of the tenant")
+	ErrCommentForbidden      = errors.New("only the author can edit a comment")
+)
+
+// CommentInput is the user-supplied part of a comment
+type CommentInput struct {
+	ParentID *string
+	Body     string
+	Mentions []string // User IDs
+}
+
+// CommentService manages work order comments and the activity timeline
+type CommentService struct {
+	repo     *repository.CommentRepository
+	timeline *repository.TimelineRepository
+	woRepo   *repository.WorkOrderRepository
+	users    *repository.UserRepository
+	audit    *AuditService
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(repo *repository.CommentRepository, timeline *repository.TimelineRepository, woRepo *repository.WorkOrderRepository, users *repository.UserRepository, audit *AuditService) *CommentService {
+	return &CommentService{repo: repo, timeline: timeline, woRepo: woRepo, users: users, audit: audit}
+}
+
+// List returns a work order's comments as threads: top-level comments, oldest
+// first, each with its replies
+func (s *CommentService) List(ctx context.Context, claims *auth.Claims, workOrderID string) ([]model.WOComment, error) {
+	if _, err := s.workOrderForTenant(ctx, claims, workOrderID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.ListByWorkOrder(ctx, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := []model.WOComment{}
+	index := make(map[string]int)
+	for _, c := range comments {
+		if c.ParentID == nil {
+			index[c.ID] = len(threads)
+			threads = append(threads, c)
+		}
+	}
+	for _, c := range comments {
+		if c.ParentID == nil {
+			continue
+		}
+		if i, ok := index[*c.ParentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, c)
+		}
+	}
+
+	return threads, nil
+}
+
+// Create adds a comment to a work order. A reply to a reply joins the top-level
+// comment's thread.
+func (s *CommentService) Create(ctx context.Context, claims *auth.Claims, workOrderID string, in CommentInput) (*model.WOComment, error) {
+	wo, err := s.workOrderForTenant(ctx, claims, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &model.WOComment{
+		WorkOrderID: wo.ID,
+		UserID:      claims.UserID,
+	}
+
+	if in.ParentID != nil && *in.ParentID != "" {
+		parent, err := s.repo.FindByID(ctx, *in.ParentID)
+		if err != nil || parent.WorkOrderID != wo.ID {
+			return nil, ErrCommentInvalidParent
+		}
+		rootID := parent.ID
+		if parent.ParentID != nil {
+			rootID = *parent.ParentID
+		}
+		c.ParentID = &rootID
+	}
+
+	if err := s.prepare(ctx, claims, c, in); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionCreate, model.AuditEntityWorkOrder, wo.ID, map[string]interface{}{
+		"commentId": c.ID,
+		"mentions":  c.Mentions,
+	})
+
+	return s.repo.FindByID(ctx, c.ID)
+}
+
+// Edit replaces the body and mentions of the caller's own comment
+func (s *CommentService) Edit(ctx context.Context, claims *auth.Claims, workOrderID, commentID string, in CommentInput) (*model.WOComment, error) {
+	wo, err := s.workOrderForTenant(ctx, claims, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.repo.FindByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.WorkOrderID != wo.ID {
+		return nil, repository.ErrCommentNotFound
+	}
+	if c.UserID != claims.UserID {
+		return nil, ErrCommentForbidden
+	}
+
+	if err := s.prepare(ctx, claims, c, in); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionUpdate, model.AuditEntityWorkOrder, wo.ID, map[string]interface{}{
+		"commentId": c.ID,
+		"mentions":  c.Mentions,
+	})
+
+	return s.repo.FindByID(ctx, c.ID)
+}
+
+// ListMentions returns the latest comments mentioning the caller
+func (s *CommentService) ListMentions(ctx context.Context, claims *auth.Claims, limit int) ([]model.WOComment, error) {
+	return s.repo.ListMentions(ctx, claims.UserID, limit)
+}
+
+// Timeline returns a work order's merged activity, oldest first
+func (s *CommentService) Timeline(ctx context.Context, claims *auth.Claims, workOrderID string) ([]model.TimelineEntry, error) {
+	if _, err := s.workOrderForTenant(ctx, claims, workOrderID); err != nil {
+		return nil, err
+	}
+	return s.timeline.ForWorkOrder(ctx, workOrderID)
+}
+
+// prepare validates the body and mentions of in and copies them onto c
+func (s *CommentService) prepare(ctx context.Context, claims *auth.Claims, c *model.WOComment, in CommentInput) error {
+	body := strings.TrimSpace(in.Body)
+	if body == "" {
+		return ErrCommentEmpty
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return ErrCommentTooLong
+	}
+
+	mentions := []string{}
+	seen := make(map[string]bool)
+	for _, id := range in.Mentions {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		user, err := s.users.FindByID(ctx, id)
+		if err != nil || user.TenantID != claims.TenantID || !user.IsActive {
+			return ErrCommentInvalidMention
+		}
+		mentions = append(mentions, id)
+	}
+
+	c.Body = body
+	c.Mentions = mentions
+	return nil
+}
+
+// workOrderForTenant loads a work order and hides those owned by other tenants
+func (s *CommentService) workOrderForTenant(ctx context.Context, claims *auth.Claims, id string) (*model.WorkOrder, error) {
+	wo, err := s.woRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wo.TenantID != claims.TenantID {
+		return nil, ErrWorkOrderForbidden
+	}
+	return wo, nil
+}
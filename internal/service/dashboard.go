@@ -0,0 +1,541 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+var (
+	ErrUnknownWidget     = errors.New("unknown dashboard widget")
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrWidgetNotAllowed  = errors.New("widget is not available to the role")
+	ErrWidgetUnavailable = errors.New("widget data is unavailable")
+)
+
+// Dashboard widget limits
+const (
+	maxDashboardWidgets  = 24
+	maxWidgetHeight      = 12
+	dashboardListLimit   = 10
+	dashboardTrendWeeks  = 12
+	dashboardReportDays  = 30
+	dashboardBadActorTop = 5
+)
+
+// DashboardScope is whose dashboard data is being loaded; widgets see only the
+// tenant's data, and personal widgets only the user's
+type DashboardScope struct {
+	TenantID string
+	UserID   string
+	Role     string
+}
+
+// DashboardLayoutError reports an invalid widget placement
+type DashboardLayoutError struct {
+	Index   int
+	Err     error
+	Message string
+}
+
+func (e *DashboardLayoutError) Error() string {
+	return fmt.Sprintf("widgets[%d]: %s", e.Index, e.Message)
+}
+
+func (e *DashboardLayoutError) Unwrap() error {
+	return e.Err
+}
+
+// widgetQuery loads one widget's data
+type widgetQuery func(s *DashboardService, ctx context.Context, scope DashboardScope) (interface{}, error)
+
+// dashboardWidget is a registry entry: the widget's metadata and its query
+type dashboardWidget struct {
+	model.DashboardWidget
+	query widgetQuery
+}
+
+// dashboardWidgets is the widget registry, in catalog order. Each widget needs the
+// permission that guards the equivalent API, so a dashboard never shows more than
+// the role could fetch directly.
+var dashboardWidgets = []dashboardWidget{
+	{model.DashboardWidget{
+		ID: model.WidgetKPISummary, Title: "KPI Summary",
+		Description:  "Asset, work order and completion headline figures",
+		DefaultWidth: 12, DefaultHeight: 2,
+	}, (*DashboardService).kpiSummary},
+	{model.DashboardWidget{
+		ID: model.WidgetCriticalDown, Title: "Critical Down",
+		Description:  "Assets that are Down or Red Tagged, longest first",
+		Permission:   middleware.PermissionAssetRead,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).criticalDown},
+	{model.DashboardWidget{
+		ID: model.WidgetOverduePMs, Title: "Overdue PMs",
+		Description:  "Active PM schedules past a trigger",
+		Permission:   middleware.PermissionWORead,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).overduePMs},
+	{model.DashboardWidget{
+		ID: model.WidgetDataHealth, Title: "Meter Data Health",
+		Description:  "In-service assets without a recent meter reading",
+		Permission:   middleware.PermissionAssetRead,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).dataHealth},
+	{model.DashboardWidget{
+		ID: model.WidgetMyJobs, Title: "My Jobs",
+		Description:  "Open work orders assigned to me",
+		Permission:   middleware.PermissionWOWrite,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).myJobs},
+	{model.DashboardWidget{
+		ID: model.WidgetMyWallet, Title: "My Wallet",
+		Description:  "Parts I am holding",
+		Permission:   middleware.PermissionWOWrite,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).myWallet},
+	{model.DashboardWidget{
+		ID: model.WidgetTeamWorkload, Title: "Team Status",
+		Description:  "Open work per technician and unassigned work",
+		Permission:   middleware.PermissionWOAssign,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).teamWorkload},
+	{model.DashboardWidget{
+		ID: model.WidgetSLAOverdue, Title: "SLA Overdue",
+		Description:  "Open work orders past their respond-by or resolve-by time",
+		Permission:   middleware.PermissionWOAssign,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).slaOverdue},
+	{model.DashboardWidget{
+		ID: model.WidgetLowStock, Title: "Low Stock",
+		Description:  "Stock below its minimum level",
+		Permission:   middleware.PermissionInventoryRead,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).lowStock},
+	{model.DashboardWidget{
+		ID: model.WidgetWalletWatch, Title: "Wallet Watch",
+		Description:  "Technician wallets holding more than the tenant threshold",
+		Permission:   middleware.PermissionInventoryRead,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).walletWatch},
+	{model.DashboardWidget{
+		ID: model.WidgetSpendTrend, Title: "Spend Trend",
+		Description:  "Weekly labor and parts cost over the last 12 weeks",
+		Permission:   middleware.PermissionReportView,
+		DefaultWidth: 12, DefaultHeight: 4,
+	}, (*DashboardService).spendTrend},
+	{model.DashboardWidget{
+		ID: model.WidgetBadActors, Title: "Bad Actors",
+		Description:  "Costliest assets over the last 30 days",
+		Permission:   middleware.PermissionReportView,
+		DefaultWidth: 6, DefaultHeight: 4,
+	}, (*DashboardService).badActors},
+	{model.DashboardWidget{
+		ID: model.WidgetReliability, Title: "Reliability",
+		Description:  "Availability, MTTR and MTBF over the last 30 days",
+		Permission:   middleware.PermissionReportView,
+		DefaultWidth: 6, DefaultHeight: 2,
+	}, (*DashboardService).reliability},
+}
+
+// dashboardWidgetIndex looks up registry entries by ID
+var dashboardWidgetIndex = func() map[string]*dashboardWidget {
+	index := make(map[string]*dashboardWidget, len(dashboardWidgets))
+	for i := range dashboardWidgets {
+		index[dashboardWidgets[i].ID] = &dashboardWidgets[i]
+	}
+	return index
+}()
+
+// roleDefaultWidgets is the built-in dashboard of each role, following the PRD's
+// RBAC table: Team Status for Supervisors, Stock Reports for Storemen and
+// Financial / Strategy for Managers
+var roleDefaultWidgets = map[string][]string{
+	middleware.RoleTechnician: {model.WidgetMyJobs, model.WidgetMyWallet, model.WidgetCriticalDown},
+	middleware.RoleSupervisor: {
+		model.WidgetKPISummary, model.WidgetTeamWorkload, model.WidgetSLAOverdue,
+		model.WidgetCriticalDown, model.WidgetOverduePMs,
+	},
+	middleware.RoleStoreman: {model.WidgetLowStock, model.WidgetWalletWatch},
+	middleware.RoleManager: {
+		model.WidgetKPISummary, model.WidgetSpendTrend, model.WidgetBadActors,
+		model.WidgetReliability, model.WidgetCriticalDown,
+	},
+	middleware.RoleAdmin: {
+		model.WidgetKPISummary, model.WidgetCriticalDown, model.WidgetOverduePMs,
+		model.WidgetTeamWorkload, model.WidgetLowStock, model.WidgetDataHealth,
+	},
+	middleware.RoleViewer: {model.WidgetKPISummary},
+}
+
+// DashboardService composes role and user dashboards from the widget registry
+type DashboardService struct {
+	layouts   *repository.DashboardRepository
+	analytics *repository.AnalyticsRepository
+	woRepo    *repository.WorkOrderRepository
+	pmRepo    *repository.PMRepository
+	inventory *repository.InventoryRepository
+}
+
+// NewDashboardService creates a new dashboard service
+func NewDashboardService(layouts *repository.DashboardRepository, analytics *repository.AnalyticsRepository, woRepo *repository.WorkOrderRepository, pmRepo *repository.PMRepository, inventory *repository.InventoryRepository) *DashboardService {
+	return &DashboardService{layouts: layouts, analytics: analytics, woRepo: woRepo, pmRepo: pmRepo, inventory: inventory}
+}
+
+// IsDashboardRole reports whether role has a dashboard (every known role does)
+func IsDashboardRole(role string) bool {
+	_, ok := roleDefaultWidgets[role]
+	return ok
+}
+
+// Widgets lists the widgets available to the role
+func (s *DashboardService) Widgets(role string) []model.DashboardWidget {
+	widgets := []model.DashboardWidget{}
+	for _, w := range dashboardWidgets {
+		if widgetAllowed(&w, role) {
+			widgets = append(widgets, w.DashboardWidget)
+		}
+	}
+	return widgets
+}
+
+// ForUser resolves the user's dashboard: their own layout, else the tenant's
+// default for their role, else the built-in one. Widgets the role can no longer
+// see are dropped.
+func (s *DashboardService) ForUser(ctx context.Context, scope DashboardScope) (*model.DashboardLayout, error) {
+	layout, err := s.layouts.FindForUser(ctx, scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if layout == nil {
+		return s.ForRole(ctx, scope.TenantID, scope.Role)
+	}
+	layout.Role = scope.Role
+	layout.Widgets = visiblePlacements(layout.Widgets, scope.Role)
+	return layout, nil
+}
+
+// ForRole resolves the tenant's dashboard for a role, falling back to the built-in one
+func (s *DashboardService) ForRole(ctx context.Context, tenantID, role string) (*model.DashboardLayout, error) {
+	if !IsDashboardRole(role) {
+		return nil, ErrUnknownRole
+	}
+
+	layout, err := s.layouts.FindForRole(ctx, tenantID, role)
+	if err != nil {
+		return nil, err
+	}
+	if layout == nil {
+		return DefaultDashboard(role), nil
+	}
+	layout.Widgets = visiblePlacements(layout.Widgets, role)
+	return layout, nil
+}
+
+// SaveForUser validates and stores the user's own layout
+func (s *DashboardService) SaveForUser(ctx context.Context, scope DashboardScope, widgets []model.DashboardPlacement) (*model.DashboardLayout, error) {
+	placements, err := validatePlacements(widgets, scope.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedAt, err := s.layouts.SaveForUser(ctx, scope.TenantID, scope.UserID, placements)
+	if err != nil {
+		return nil, err
+	}
+	return &model.DashboardLayout{
+		Source: model.DashboardSourceUser, Role: scope.Role, Widgets: placements, UpdatedAt: &updatedAt,
+	}, nil
+}
+
+// SaveForRole validates and stores the tenant's default layout for a role
+func (s *DashboardService) SaveForRole(ctx context.Context, tenantID, role, updatedBy string, widgets []model.DashboardPlacement) (*model.DashboardLayout, error) {
+	if !IsDashboardRole(role) {
+		return nil, ErrUnknownRole
+	}
+	placements, err := validatePlacements(widgets, role)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedAt, err := s.layouts.SaveForRole(ctx, tenantID, role, updatedBy, placements)
+	if err != nil {
+		return nil, err
+	}
+	return &model.DashboardLayout{
+		Source: model.DashboardSourceRole, Role: role, Widgets: placements, UpdatedAt: &updatedAt,
+	}, nil
+}
+
+// ResetUser removes the user's own layout and returns the one they fall back to
+func (s *DashboardService) ResetUser(ctx context.Context, scope DashboardScope) (*model.DashboardLayout, error) {
+	if err := s.layouts.DeleteForUser(ctx, scope.UserID); err != nil {
+		return nil, err
+	}
+	return s.ForRole(ctx, scope.TenantID, scope.Role)
+}
+
+// ResetRole removes the tenant's layout for a role and returns the built-in one
+func (s *DashboardService) ResetRole(ctx context.Context, tenantID, role string) (*model.DashboardLayout, error) {
+	if !IsDashboardRole(role) {
+		return nil, ErrUnknownRole
+	}
+	if err := s.layouts.DeleteForRole(ctx, tenantID, role); err != nil {
+		return nil, err
+	}
+	return DefaultDashboard(role), nil
+}
+
+// WidgetData loads one widget's data for the scope
+func (s *DashboardService) WidgetData(ctx context.Context, scope DashboardScope, widgetID string) (interface{}, error) {
+	w, ok := dashboardWidgetIndex[widgetID]
+	if !ok {
+		return nil, ErrUnknownWidget
+	}
+	if !widgetAllowed(w, scope.Role) {
+		return nil, ErrWidgetNotAllowed
+	}
+	return w.query(s, ctx, scope)
+}
+
+// LayoutData loads the data of every widget on the user's dashboard. A failing
+// widget is reported in its own entry rather than failing the dashboard.
+func (s *DashboardService) LayoutData(ctx context.Context, scope DashboardScope) (*model.DashboardLayout, map[string]model.DashboardWidgetData, error) {
+	layout, err := s.ForUser(ctx, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := make(map[string]model.DashboardWidgetData, len(layout.Widgets))
+	for _, p := range layout.Widgets {
+		if _, done := data[p.Widget]; done {
+			continue
+		}
+		d, err := s.WidgetData(ctx, scope, p.Widget)
+		if err != nil {
+			slog.Error("Failed to load dashboard widget",
+				slog.String("widget", p.Widget), slog.String("error", err.Error()))
+			data[p.Widget] = model.DashboardWidgetData{Error: ErrWidgetUnavailable.Error()}
+			continue
+		}
+		data[p.Widget] = model.DashboardWidgetData{Data: d}
+	}
+	return layout, data, nil
+}
+
+// DefaultDashboard returns the built-in dashboard of a role, stacked row by row
+func DefaultDashboard(role string) *model.DashboardLayout {
+	placements := make([]model.DashboardPlacement, 0, len(roleDefaultWidgets[role]))
+	for _, id := range roleDefaultWidgets[role] {
+		placements = append(placements, model.DashboardPlacement{Widget: id})
+	}
+	return &model.DashboardLayout{
+		Source:  model.DashboardSourceDefault,
+		Role:    role,
+		Widgets: packPlacements(visiblePlacements(placements, role)),
+	}
+}
+
+// widgetAllowed reports whether the role may see the widget
+func widgetAllowed(w *dashboardWidget, role string) bool {
+	return w.Permission == "" || middleware.HasPermission(role, w.Permission)
+}
+
+// visiblePlacements drops placements of unknown widgets or widgets the role may not see
+func visiblePlacements(placements []model.DashboardPlacement, role string) []model.DashboardPlacement {
+	visible := make([]model.DashboardPlacement, 0, len(placements))
+	for _, p := range placements {
+		if w, ok := dashboardWidgetIndex[p.Widget]; ok && widgetAllowed(w, role) {
+			visible = append(visible, p)
+		}
+	}
+	return visible
+}
+
+// validatePlacements checks a submitted layout against the registry and grid,
+// filling in default sizes
+func validatePlacements(placements []model.DashboardPlacement, role string) ([]model.DashboardPlacement, error) {
+	if len(placements) > maxDashboardWidgets {
+		return nil, &DashboardLayoutError{Index: maxDashboardWidgets, Message: fmt.Sprintf("At most %d widgets", maxDashboardWidgets)}
+	}
+
+	seen := map[string]bool{}
+	validated := make([]model.DashboardPlacement, 0, len(placements))
+	for i, p := range placements {
+		w, ok := dashboardWidgetIndex[p.Widget]
+		if !ok {
+			return nil, &DashboardLayoutError{Index: i, Err: ErrUnknownWidget, Message: "Unknown widget " + p.Widget}
+		}
+		if !widgetAllowed(w, role) {
+			return nil, &DashboardLayoutError{Index: i, Err: ErrWidgetNotAllowed, Message: "Widget " + p.Widget + " is not available to " + role}
+		}
+		if seen[p.Widget] {
+			return nil, &DashboardLayoutError{Index: i, Message: "Widget " + p.Widget + " is placed twice"}
+		}
+		seen[p.Widget] = true
+
+		if p.W == 0 {
+			p.W = w.DefaultWidth
+		}
+		if p.H == 0 {
+			p.H = w.DefaultHeight
+		}
+		switch {
+		case p.X < 0 || p.Y < 0:
+			return nil, &DashboardLayoutError{Index: i, Message: "Position must not be negative"}
+		case p.W < 1 || p.X+p.W > model.DashboardGridColumns:
+			return nil, &DashboardLayoutError{Index: i, Message: fmt.Sprintf("Must fit within %d columns", model.DashboardGridColumns)}
+		case p.H < 1 || p.H > maxWidgetHeight:
+			return nil, &DashboardLayoutError{Index: i, Message: fmt.Sprintf("Height must be between 1 and %d", maxWidgetHeight)}
+		}
+		validated = append(validated, p)
+	}
+	return validated, nil
+}
+
+// packPlacements lays widgets out left to right, wrapping rows at the grid width
+func packPlacements(placements []model.DashboardPlacement) []model.DashboardPlacement {
+	x, y, rowHeight := 0, 0, 0
+	for i := range placements {
+		p := &placements[i]
+		w := dashboardWidgetIndex[p.Widget]
+		p.W, p.H = w.DefaultWidth, w.DefaultHeight
+		if x+p.W > model.DashboardGridColumns {
+			x, y, rowHeight = 0, y+rowHeight, 0
+		}
+		p.X, p.Y = x, y
+		x += p.W
+		rowHeight = max(rowHeight, p.H)
+	}
+	return placements
+}
+
+// ==================== WIDGET QUERIES ====================
+
+func (s *DashboardService) kpiSummary(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	stats, err := s.analytics.GetDashboardStats(ctx, scope.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.pmRepo.ListForEvaluation(ctx, scope.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	stats.OverduePMs = len(model.OverdueSchedules(schedules, time.Now()))
+	return stats, nil
+}
+
+func (s *DashboardService) criticalDown(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	return s.analytics.ListCriticalDown(ctx, scope.TenantID)
+}
+
+func (s *DashboardService) overduePMs(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	schedules, err := s.pmRepo.ListForEvaluation(ctx, scope.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	return model.OverdueSchedules(schedules, time.Now()), nil
+}
+
+func (s *DashboardService) dataHealth(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	return s.analytics.ListStaleMeters(ctx, scope.TenantID)
+}
+
+func (s *DashboardService) myJobs(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	return s.woRepo.List(ctx, model.WorkOrderListParams{
+		TenantID:       scope.TenantID,
+		AssignedUserID: scope.UserID,
+		OpenOnly:       true,
+		Page:           1,
+		Limit:          dashboardListLimit,
+	})
+}
+
+func (s *DashboardService) myWallet(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	return s.inventory.ListWallets(ctx, scope.UserID)
+}
+
+func (s *DashboardService) teamWorkload(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	workload, err := s.woRepo.Workload(ctx, scope.TenantID, []string{middleware.RoleTechnician})
+	if err != nil {
+		return nil, err
+	}
+	unassigned, err := s.woRepo.CountUnassignedOpen(ctx, scope.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"technicians": workload, "unassigned": unassigned}, nil
+}
+
+func (s *DashboardService) slaOverdue(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	return s.woRepo.List(ctx, model.WorkOrderListParams{
+		TenantID: scope.TenantID,
+		OpenOnly: true,
+		Overdue:  true,
+		Page:     1,
+		Limit:    dashboardListLimit,
+	})
+}
+
+func (s *DashboardService) lowStock(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	return s.inventory.ListStock(ctx, model.InventoryStockListParams{
+		TenantID: scope.TenantID,
+		LowStock: true,
+		Page:     1,
+		Limit:    dashboardListLimit,
+	})
+}
+
+func (s *DashboardService) walletWatch(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	return s.analytics.ListWalletWatch(ctx, scope.TenantID)
+}
+
+func (s *DashboardService) spendTrend(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	to := model.BriefWeekStart(time.Now()).AddDate(0, 0, 7)
+	return s.analytics.GetTrends(ctx, scope.TenantID, model.TrendIntervalWeek, to.AddDate(0, 0, -7*dashboardTrendWeeks), to)
+}
+
+func (s *DashboardService) badActors(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	from, to := dashboardReportPeriod()
+	actors, _, err := s.analytics.GetBadActors(ctx, model.BadActorParams{
+		TenantID: scope.TenantID,
+		GroupBy:  model.ReliabilityByAsset,
+		SortBy:   model.BadActorByTotalCost,
+		From:     from,
+		To:       to,
+		Limit:    dashboardBadActorTop,
+	})
+	return actors, err
+}
+
+func (s *DashboardService) reliability(ctx context.Context, scope DashboardScope) (interface{}, error) {
+	from, to := dashboardReportPeriod()
+	groups, err := s.analytics.GetReliability(ctx, model.ReliabilityParams{
+		TenantID: scope.TenantID,
+		GroupBy:  model.ReliabilityByAsset,
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		return nil, err
+	}
+	summary := model.ReliabilityMetrics{Key: "all", Label: "All assets"}
+	for _, g := range groups {
+		summary.Add(g)
+	}
+	summary.Compute()
+	return summary, nil
+}
+
+// dashboardReportPeriod is the rolling window of the report widgets, in whole UTC days
+func dashboardReportPeriod() (time.Time, time.Time) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	return to.AddDate(0, 0, -dashboardReportDays), to
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"ioi-amms/internal/auth"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+var (
+	ErrInvalidPartSource     = errors.New("source must be Wallet or Warehouse")
+	ErrPartLocationRequired  = errors.New("locationId is required when consuming from the warehouse")
+	ErrInvalidPartQuantity   = errors.New("quantity must be greater than 0")
+	ErrPartsWorkOrderNotOpen = errors.New("parts cannot be consumed on a closed or cancelled work order")
+	ErrPartForbidden         = errors.New("part belongs to another tenant")
+)
+
+// PartConsumption is a request to use a part on a work order
+type PartConsumption struct {
+	PartID     string
+	Quantity   float64
+	Source     string // Wallet (default) or Warehouse
+	LocationID *string
+}
+
+// InventoryService applies inventory movements driven by work order execution
+type InventoryService struct {
+	repo   *repository.InventoryRepository
+	woRepo *repository.WorkOrderRepository
+}
+
+// NewInventoryService creates a new inventory service
+func NewInventoryService(repo *repository.InventoryRepository, woRepo *repository.WorkOrderRepository) *InventoryService {
+	return &InventoryService{repo: repo, woRepo: woRepo}
+}
+
+// ConsumePart expenses a part to a work order (and so to its asset), drawing it from
+// the caller's wallet or from warehouse stock. The log row, the stock decrement and
+// the audit entry are written in one transaction.
+func (s *InventoryService) ConsumePart(ctx context.Context, claims *auth.Claims, workOrderID string, req PartConsumption) (*model.ResourceLog, error) {
+	wo, err := s.workOrderForTenant(ctx, claims, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if wo.Status == model.WOStatusClosed || wo.Status == model.WOStatusCancelled {
+		return nil, ErrPartsWorkOrderNotOpen
+	}
+
+	if req.Source == "" {
+		req.Source = model.ResourceSourceWallet
+	}
+	switch {
+	case req.Source != model.ResourceSourceWallet && req.Source != model.ResourceSourceWarehouse:
+		return nil, ErrInvalidPartSource
+	case req.Source == model.ResourceSourceWarehouse && (req.LocationID == nil || *req.LocationID == ""):
+		return nil, ErrPartLocationRequired
+	case req.Quantity <= 0:
+		return nil, ErrInvalidPartQuantity
+	}
+
+	part, err := s.repo.FindPartByID(ctx, req.PartID)
+	if err != nil {
+		return nil, err
+	}
+	if part.TenantID != claims.TenantID {
+		return nil, ErrPartForbidden
+	}
+
+	rl := &model.ResourceLog{
+		WorkOrderID: wo.ID,
+		PartID:      &part.ID,
+		PartName:    &part.Name,
+		Quantity:    req.Quantity,
+		Source:      req.Source,
+		UserID:      &claims.UserID,
+		PartSKU:     part.SKU,
+	}
+	if req.Source == model.ResourceSourceWarehouse {
+		rl.LocationID = req.LocationID
+	}
+
+	audit := &model.AuditLog{
+		UserID:     claims.UserID,
+		Action:     model.AuditActionConsume,
+		EntityType: model.AuditEntityWorkOrder,
+		EntityID:   wo.ID,
+		Changes: map[string]interface{}{
+			"partId":   part.ID,
+			"sku":      part.SKU,
+			"quantity": req.Quantity,
+			"source":   req.Source,
+		},
+	}
+	if rl.LocationID != nil {
+		audit.Changes["locationId"] = *rl.LocationID
+	}
+
+	if err := s.repo.ConsumePart(ctx, claims.TenantID, rl, audit); err != nil {
+		return nil, err
+	}
+	return rl, nil
+}
+
+// ListConsumption returns the parts consumed on a work order
+func (s *InventoryService) ListConsumption(ctx context.Context, claims *auth.Claims, workOrderID string) ([]model.ResourceLog, error) {
+	if _, err := s.workOrderForTenant(ctx, claims, workOrderID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListResourceLogs(ctx, workOrderID)
+}
+
+// workOrderForTenant loads a work order and hides those owned by other tenants
+func (s *InventoryService) workOrderForTenant(ctx context.Context, claims *auth.Claims, id string) (*model.WorkOrder, error) {
+	wo, err := s.woRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wo.TenantID != claims.TenantID {
+		return nil, ErrWorkOrderForbidden
+	}
+	return wo, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ioi-amms/internal/auth"
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+var (
+	ErrLaborWorkOrderClosed = errors.New("labor cannot be logged on a closed or cancelled work order")
+	ErrLaborForbidden       = errors.New("insufficient permissions to log labor for another user")
+	ErrLaborInvalidUser     = errors.New("labor user must be an active user of the tenant")
+	ErrLaborInvalidHours    = errors.New("hours must be greater than 0 and at most 24")
+	ErrLaborFutureDate      = errors.New("labor cannot be logged for a future date")
+)
+
+// LaborEntry is the user-supplied part of a labor log
+type LaborEntry struct {
+	UserID        string // Defaults to the caller
+	HoursSpent    *float64
+	DatePerformed *time.Time // Defaults to today
+	Comment       *string
+}
+
+// LaborService records time spent on work orders
+type LaborService struct {
+	repo   *repository.LaborRepository
+	woRepo *repository.WorkOrderRepository
+	users  *repository.UserRepository
+	audit  *AuditService
+}
+
+// NewLaborService creates a new labor service
+func NewLaborService(repo *repository.LaborRepository, woRepo *repository.WorkOrderRepository, users *repository.UserRepository, audit *AuditService) *LaborService {
+	return &LaborService{repo: repo, woRepo: woRepo, users: users, audit: audit}
+}
+
+// List returns a work order's labor entries and totals
+func (s *LaborService) List(ctx context.Context, claims *auth.Claims, workOrderID string) ([]model.LaborLog, model.LaborSummary, error) {
+	if _, err := s.workOrderForTenant(ctx, claims, workOrderID); err != nil {
+		return nil, model.LaborSummary{}, err
+	}
+	return s.repo.ListByWorkOrder(ctx, workOrderID)
+}
+
+// Log records labor on a work order. Users log their own time; logging for
+// someone else requires wo:assign.
+func (s *LaborService) Log(ctx context.Context, claims *auth.Claims, workOrderID string, entry LaborEntry) (*model.LaborLog, error) {
+	wo, err := s.openWorkOrder(ctx, claims, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := entry.UserID
+	if userID == "" {
+		userID = claims.UserID
+	}
+	if userID != claims.UserID {
+		if !middleware.HasPermission(claims.Role, middleware.PermissionWOAssign) {
+			return nil, ErrLaborForbidden
+		}
+		user, err := s.users.FindByID(ctx, userID)
+		if err != nil || user.TenantID != claims.TenantID || !user.IsActive {
+			return nil, ErrLaborInvalidUser
+		}
+	}
+
+	if entry.HoursSpent == nil {
+		return nil, ErrLaborInvalidHours
+	}
+	log := &model.LaborLog{
+		WorkOrderID: wo.ID,
+		UserID:      userID,
+		HoursSpent:  *entry.HoursSpent,
+		Comment:     entry.Comment,
+	}
+	if entry.DatePerformed != nil {
+		log.DatePerformed = *entry.DatePerformed
+	}
+	if err := validateLabor(log); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, log); err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionCreate, model.AuditEntityWorkOrder, wo.ID, map[string]interface{}{
+		"laborLogId": log.ID,
+		"userId":     log.UserID,
+		"hoursSpent": log.HoursSpent,
+	})
+
+	// Reload for the resolved rate and cost
+	return s.repo.FindByID(ctx, log.ID)
+}
+
+// Edit updates a labor entry. Users edit their own entries; editing others' requires wo:assign.
+func (s *LaborService) Edit(ctx context.Context, claims *auth.Claims, workOrderID, logID string, entry LaborEntry) (*model.LaborLog, error) {
+	wo, err := s.openWorkOrder(ctx, claims, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	log, err := s.repo.FindByID(ctx, logID)
+	if err != nil {
+		return nil, err
+	}
+	if log.WorkOrderID != wo.ID {
+		return nil, repository.ErrLaborLogNotFound
+	}
+	if log.UserID != claims.UserID && !middleware.HasPermission(claims.Role, middleware.PermissionWOAssign) {
+		return nil, ErrLaborForbidden
+	}
+
+	before := log.HoursSpent
+	if entry.HoursSpent != nil {
+		log.HoursSpent = *entry.HoursSpent
+	}
+	if entry.DatePerformed != nil {
+		log.DatePerformed = *entry.DatePerformed
+	}
+	if entry.Comment != nil {
+		log.Comment = entry.Comment
+	}
+	if err := validateLabor(log); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, log); err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionUpdate, model.AuditEntityWorkOrder, wo.ID, map[string]interface{}{
+		"laborLogId": log.ID,
+		"hoursSpent": map[string]float64{"from": before, "to": log.HoursSpent},
+	})
+
+	return s.repo.FindByID(ctx, log.ID)
+}
+
+// openWorkOrder loads a tenant's work order that still accepts labor entries
+func (s *LaborService) openWorkOrder(ctx context.Context, claims *auth.Claims, id string) (*model.WorkOrder, error) {
+	wo, err := s.workOrderForTenant(ctx, claims, id)
+	if err != nil {
+		return nil, err
+	}
+	if wo.Status == model.WOStatusClosed || wo.Status == model.WOStatusCancelled {
+		return nil, ErrLaborWorkOrderClosed
+	}
+	return wo, nil
+}
+
+// workOrderForTenant loads a work order and hides those owned by other tenants
+func (s *LaborService) workOrderForTenant(ctx context.Context, claims *auth.Claims, id string) (*model.WorkOrder, error) {
+	wo, err := s.woRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wo.TenantID != claims.TenantID {
+		return nil, ErrWorkOrderForbidden
+	}
+	return wo, nil
+}
+
+// validateLabor checks hours are within a single day and the date is not in the future
+func validateLabor(l *model.LaborLog) error {
+	if l.HoursSpent <= 0 || l.HoursSpent > 24 {
+		return ErrLaborInvalidHours
+	}
+	if !l.DatePerformed.IsZero() && l.DatePerformed.After(time.Now()) {
+		return ErrLaborFutureDate
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ioi-amms/internal/auth"
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/report"
+	"ioi-amms/internal/repository"
+)
+
+var (
+	ErrInvalidTransferType = errors.New("unknown transfer type")
+	ErrMovementTransition  = errors.New("asset movement status transition not allowed")
+	ErrMovementForbidden   = errors.New("asset movement status change not permitted for role")
+	ErrMovementTenant      = errors.New("asset movement belongs to another tenant")
+	ErrShipmentNotApproved = errors.New("shipment has not been approved")
+)
+
+// missingReason is the dispute reason recorded for items reported missing on receipt
+const missingReason = "Missing on receipt"
+
+// transferApprovalPermission lets Supervisors and above ship assets directly and
+// approve the transfers requested by everyone else
+const transferApprovalPermission = middleware.PermissionAssetWrite
+
+// movementPermissions gates each movement status change. Anyone who can see assets
+// may receive or dispute a shipment; approvals and resolving a dispute need a
+// Supervisor.
+var movementPermissions = map[statusEdge]string{
+	{model.MovementStatusPendingApproval, model.MovementStatusInTransit}: transferApprovalPermission,
+	{model.MovementStatusPendingApproval, model.MovementStatusRejected}:  transferApprovalPermission,
+	{model.MovementStatusInTransit, model.MovementStatusReceived}:        middleware.PermissionAssetRead,
+	{model.MovementStatusInTransit, model.MovementStatusDisputed}:        middleware.PermissionAssetRead,
+	{model.MovementStatusReceived, model.MovementStatusDisputed}:         middleware.PermissionAssetRead,
+	{model.MovementStatusDisputed, model.MovementStatusReceived}:         transferApprovalPermission,
+}
+
+// MovementTransitionError carries the context of a rejected movement status change
+type MovementTransitionError struct {
+	Err     error
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *MovementTransitionError) Error() string {
+	return e.Err.Error() + ": " + e.From + " -> " + e.To
+}
+
+func (e *MovementTransitionError) Unwrap() error {
+	return e.Err
+}
+
+// MovementService runs asset transfers through their chain of custody
+type MovementService struct {
+	repo    *repository.MovementRepository
+	tenants *repository.TenantRepository
+	audit   *AuditService
+}
+
+// NewMovementService creates a new asset movement service
+func NewMovementService(repo *repository.MovementRepository, tenants *repository.TenantRepository, audit *AuditService) *MovementService {
+	return &MovementService{repo: repo, tenants: tenants, audit: audit}
+}
+
+// CreateTransfer ships assets to a location under a new shipment reference.
+// Supervisors and above put the shipment straight in transit; anyone else's
+// transfer waits for approval.
+func (s *MovementService) CreateTransfer(ctx context.Context, claims *auth.Claims, req model.TransferRequest) (string, []model.AssetMovement, error) {
+	if !model.IsValidTransferType(req.TransferType) {
+		return "", nil, ErrInvalidTransferType
+	}
+
+	req.TenantID = claims.TenantID
+	req.UserID = claims.UserID
+	req.AssetIDs = uniqueStrings(req.AssetIDs)
+	req.Status = model.MovementStatusPendingApproval
+	if middleware.HasPermission(claims.Role, transferApprovalPermission) {
+		req.Status = model.MovementStatusInTransit
+	}
+
+	ref, movements, err := s.repo.CreateTransfer(ctx, req)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, m := range movements {
+		s.audit.Log(ctx, claims.UserID, model.AuditActionCreate, model.AuditEntityMovement, m.ID, map[string]interface{}{
+			"assetId":      m.AssetID,
+			"toLocationId": req.ToLocationID,
+			"transferType": req.TransferType,
+			"shipmentRef":  ref,
+			"status":       m.Status,
+		})
+	}
+	return ref, movements, nil
+}
+
+// Get retrieves a movement of the caller's tenant
+func (s *MovementService) Get(ctx context.Context, claims *auth.Claims, id string) (*model.AssetMovement, error) {
+	m, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.TenantID != claims.TenantID {
+		return nil, ErrMovementTenant
+	}
+	return m, nil
+}
+
+// ReviewShipment approves or rejects every movement of a shipment awaiting approval
+func (s *MovementService) ReviewShipment(ctx context.Context, claims *auth.Claims, ref string, approve bool, note *string) ([]model.AssetMovement, error) {
+	to := model.MovementStatusRejected
+	if approve {
+		to = model.MovementStatusInTransit
+	}
+	if err := checkMovementPermission(claims, model.MovementStatusPendingApproval, to); err != nil {
+		return nil, err
+	}
+
+	movements, err := s.repo.ReviewShipment(ctx, claims.TenantID, ref, to, claims.UserID, note)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range movements {
+		if m.Status != to || m.ReviewedByUserID == nil || *m.ReviewedByUserID != claims.UserID {
+			continue
+		}
+		changes := map[string]interface{}{"from": model.MovementStatusPendingApproval, "to": to, "shipmentRef": ref}
+		if note != nil {
+			changes["note"] = *note
+		}
+		s.audit.Log(ctx, claims.UserID, model.AuditActionStatusChange, model.AuditEntityMovement, m.ID, changes)
+	}
+	return movements, nil
+}
+
+// Receive marks a movement received, putting its asset at the destination
+func (s *MovementService) Receive(ctx context.Context, claims *auth.Claims, id string) (*model.AssetMovement, error) {
+	m, err := s.Get(ctx, claims, id)
+	if err != nil {
+		return nil, err
+	}
+	from := m.Status
+	if err := checkMovementTransition(claims, from, model.MovementStatusReceived); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Receive(ctx, m, claims.UserID); err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionStatusChange, model.AuditEntityMovement, m.ID, map[string]interface{}{
+		"from":        from,
+		"to":          m.Status,
+		"shipmentRef": m.ShipmentRef,
+	})
+	return m, nil
+}
+
+// Dispute flags a movement as damaged, wrong or missing
+func (s *MovementService) Dispute(ctx context.Context, claims *auth.Claims, id, reason string) (*model.AssetMovement, error) {
+	m, err := s.Get(ctx, claims, id)
+	if err != nil {
+		return nil, err
+	}
+	from := m.Status
+	if err := checkMovementTransition(claims, from, model.MovementStatusDisputed); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Dispute(ctx, m, reason, claims.UserID); err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionStatusChange, model.AuditEntityMovement, m.ID, map[string]interface{}{
+		"from":        from,
+		"to":          m.Status,
+		"shipmentRef": m.ShipmentRef,
+		"reason":      reason,
+	})
+	return m, nil
+}
+
+// GatePass renders the gate pass PDF of an approved shipment, listing every asset
+// that has not been rejected
+func (s *MovementService) GatePass(ctx context.Context, claims *auth.Claims, ref string) ([]byte, error) {
+	movements, err := s.repo.ListShipment(ctx, claims.TenantID, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &model.GatePass{ShipmentRef: ref, GeneratedAt: time.Now(), GeneratedBy: claims.Email}
+	for _, m := range movements {
+		switch m.Status {
+		case model.MovementStatusPendingApproval:
+			return nil, ErrShipmentNotApproved
+		case model.MovementStatusRejected:
+			continue
+		}
+		g.Items = append(g.Items, model.GatePassItem{AssetMovement: m})
+	}
+	if len(g.Items) == 0 {
+		return nil, ErrShipmentNotApproved
+	}
+
+	identities, err := s.repo.ListShipmentIdentities(ctx, claims.TenantID, ref)
+	if err != nil {
+		return nil, err
+	}
+	for i := range g.Items {
+		g.Items[i].Identities = identities[g.Items[i].AssetID]
+	}
+
+	tenant, err := s.tenants.GetSettings(ctx, claims.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	g.TenantName = tenant.Name
+
+	return report.GatePassPDF(g)
+}
+
+// ReceiveShipment receives a whole shipment at once, e.g. from its gate pass's
+// master QR. The assets in missing are disputed instead; note, if given, is added
+// to their dispute reason.
+func (s *MovementService) ReceiveShipment(ctx context.Context, claims *auth.Claims, ref string, missing []string, note *string) (*model.ShipmentReceipt, error) {
+	if err := checkMovementPermission(claims, model.MovementStatusInTransit, model.MovementStatusReceived); err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		if err := checkMovementPermission(claims, model.MovementStatusInTransit, model.MovementStatusDisputed); err != nil {
+			return nil, err
+		}
+	}
+
+	reason := missingReason
+	if note != nil && *note != "" {
+		reason += ": " + *note
+	}
+
+	receipt, err := s.repo.ReceiveShipment(ctx, claims.TenantID, ref, uniqueStrings(missing), reason, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := map[string]bool{}
+	for _, e := range receipt.Exceptions {
+		if e.Reason == model.ReceiptExceptionNotInTransit {
+			skipped[e.MovementID] = true
+		}
+	}
+	for _, m := range receipt.Items {
+		if skipped[m.ID] {
+			continue
+		}
+		changes := map[string]interface{}{
+			"from":        model.MovementStatusInTransit,
+			"to":          m.Status,
+			"shipmentRef": ref,
+			"bulk":        true,
+		}
+		if m.Status == model.MovementStatusDisputed {
+			changes["reason"] = reason
+		}
+		s.audit.Log(ctx, claims.UserID, model.AuditActionStatusChange, model.AuditEntityMovement, m.ID, changes)
+	}
+	return receipt, nil
+}
+
+// checkMovementTransition reports whether the caller may move a movement from one
+// status to another
+func checkMovementTransition(claims *auth.Claims, from, to string) error {
+	if !model.CanTransitionMovement(from, to) {
+		return &MovementTransitionError{Err: ErrMovementTransition, From: from, To: to, Allowed: model.MovementTransitions[from]}
+	}
+	return checkMovementPermission(claims, from, to)
+}
+
+func checkMovementPermission(claims *auth.Claims, from, to string) error {
+	if perm, ok := movementPermissions[statusEdge{from, to}]; ok && !middleware.HasPermission(claims.Role, perm) {
+		return ErrMovementForbidden
+	}
+	return nil
+}
+
+// uniqueStrings returns values without duplicates, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"ioi-amms/internal/auth"
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+)
+
+func TestMovementPermissionsCoverTransitions(t *testing.T) {
+	for from, targets := range model.MovementTransitions {
+		for _, to := range targets {
+			if _, ok := movementPermissions[statusEdge{from, to}]; !ok {
+				t.Errorf("no permission for %s -> %s", from, to)
+			}
+		}
+	}
+	for edge := range movementPermissions {
+		if !model.CanTransitionMovement(edge.From, edge.To) {
+			t.Errorf("permission for %s -> %s, which is not a movement transition", edge.From, edge.To)
+		}
+	}
+}
+
+func TestCheckMovementTransition(t *testing.T) {
+	tests := []struct {
+		role     string
+		from, to string
+		want     error
+	}{
+		{middleware.RoleSupervisor, model.MovementStatusPendingApproval, model.MovementStatusInTransit, nil},
+		{middleware.RoleManager, model.MovementStatusPendingApproval, model.MovementStatusRejected, nil},
+		{middleware.RoleTechnician, model.MovementStatusPendingApproval, model.MovementStatusInTransit, ErrMovementForbidden},
+		{middleware.RoleStoreman, model.MovementStatusPendingApproval, model.MovementStatusRejected, ErrMovementForbidden},
+
+		{middleware.RoleTechnician, model.MovementStatusInTransit, model.MovementStatusReceived, nil},
+		{middleware.RoleStoreman, model.MovementStatusInTransit, model.MovementStatusDisputed, nil},
+		{middleware.RoleTechnician, model.MovementStatusReceived, model.MovementStatusDisputed, nil},
+		{middleware.RoleViewer, model.MovementStatusInTransit, model.MovementStatusReceived, ErrMovementForbidden},
+
+		{middleware.RoleSupervisor, model.MovementStatusDisputed, model.MovementStatusReceived, nil},
+		{middleware.RoleTechnician, model.MovementStatusDisputed, model.MovementStatusReceived, ErrMovementForbidden},
+
+		{middleware.RoleAdmin, model.MovementStatusPendingApproval, model.MovementStatusReceived, ErrMovementTransition},
+		{middleware.RoleAdmin, model.MovementStatusReceived, model.MovementStatusInTransit, ErrMovementTransition},
+		{middleware.RoleAdmin, model.MovementStatusRejected, model.MovementStatusInTransit, ErrMovementTransition},
+		{middleware.RoleAdmin, model.MovementStatusDisputed, model.MovementStatusRejected, ErrMovementTransition},
+		{middleware.RoleViewer, model.MovementStatusReceived, model.MovementStatusReceived, ErrMovementTransition},
+	}
+
+	for _, tt := range tests {
+		claims := &auth.Claims{UserID: "u1", TenantID: "t1", Role: tt.role}
+		err := checkMovementTransition(claims, tt.from, tt.to)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: %s -> %s: err = %v, want %v", tt.role, tt.from, tt.to, err, tt.want)
+		}
+
+		var te *MovementTransitionError
+		if errors.As(err, &te) && (te.From != tt.from || te.To != tt.to) {
+			t.Errorf("%s -> %s: error reports %s -> %s", tt.from, tt.to, te.From, te.To)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+// PMService evaluates preventive maintenance schedules and raises work orders when they fall due
+type PMService struct {
+	repo   *repository.PMRepository
+	woRepo *repository.WorkOrderRepository
+	audit  *AuditService
+}
+
+// NewPMService creates a new PM service
+func NewPMService(repo *repository.PMRepository, woRepo *repository.WorkOrderRepository, audit *AuditService) *PMService {
+	return &PMService{repo: repo, woRepo: woRepo, audit: audit}
+}
+
+// Run evaluates all tenants' schedules every interval until ctx is cancelled.
+// A non-positive interval disables the background evaluator.
+func (s *PMService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		slog.Info("PM evaluator disabled")
+		return
+	}
+
+	slog.Info("PM evaluator started", slog.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		created, err := s.Evaluate(ctx, "")
+		if err != nil && ctx.Err() == nil {
+			slog.Error("PM evaluation failed", slog.String("error", err.Error()))
+		} else if len(created) > 0 {
+			slog.Info("PM evaluation raised work orders", slog.Int("count", len(created)))
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("PM evaluator stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate checks every active schedule (optionally for a single tenant) and creates a
+// Preventive_Auto work order for each one that is due and has no open work order yet.
+//
+// Suppression: while a schedule has an open work order, the schedules listed in its
+// suppress_pm_ids on the same asset do not fire, because the larger service covers
+// them. When a suppressing schedule fires, not-yet-started work orders of the
+// schedules it suppresses are cancelled, and the new work order explains both.
+func (s *PMService) Evaluate(ctx context.Context, tenantID string) ([]model.WorkOrder, error) {
+	schedules, err := s.repo.ListForEvaluation(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*model.PMSchedule, len(schedules))
+	for i := range schedules {
+		byID[schedules[i].ID] = &schedules[i]
+	}
+
+	now := time.Now()
+
+	// Schedules already in progress suppress their smaller services. Their work
+	// orders take on the schedules that have come due since, so closing them
+	// resets those baselines too.
+	suppressedBy := make(map[string]*model.PMSchedule)
+	for i := range schedules {
+		sch := &schedules[i]
+		if sch.OpenWorkOrderID == nil {
+			continue
+		}
+		suppressed := s.markSuppressed(sch, byID, suppressedBy, now)
+		if len(suppressed) == 0 {
+			continue
+		}
+		ids := make([]string, len(suppressed))
+		for j, t := range suppressed {
+			ids[j] = t.ID
+		}
+		if err := s.woRepo.AddSuppressedPMs(ctx, *sch.OpenWorkOrderID, ids); err != nil {
+			slog.Error("Failed to record suppressed PM schedules",
+				slog.String("work_order_id", *sch.OpenWorkOrderID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	type dueSchedule struct {
+		schedule *model.PMSchedule
+		status   model.PMDueStatus
+	}
+	var due []dueSchedule
+	for i := range schedules {
+		sch := &schedules[i]
+		if sch.OpenWorkOrderID != nil {
+			continue
+		}
+		if status := sch.CheckDue(now); status.Due {
+			due = append(due, dueSchedule{schedule: sch, status: status})
+		}
+	}
+
+	// Fire the bigger services first so they suppress the smaller ones due in the same run
+	dueSchedules := make([]*model.PMSchedule, len(due))
+	for i, d := range due {
+		dueSchedules[i] = d.schedule
+	}
+	order := suppressionOrder(dueSchedules)
+
+	created := []model.WorkOrder{}
+	for _, i := range order {
+		d := due[i]
+		sch := d.schedule
+		if by, ok := suppressedBy[sch.ID]; ok {
+			slog.Debug("PM schedule suppressed",
+				slog.String("pm_schedule_id", sch.ID),
+				slog.String("suppressed_by", by.ID),
+			)
+			continue
+		}
+
+		suppressed := s.markSuppressed(sch, byID, suppressedBy, now)
+		wo, err := s.raiseWorkOrder(ctx, sch, d.status, suppressed)
+		if err != nil {
+			if errors.Is(err, repository.ErrOpenPMWorkOrderExists) {
+				continue // Another evaluator run got there first
+			}
+			slog.Error("Failed to raise PM work order",
+				slog.String("pm_schedule_id", sch.ID),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		created = append(created, *wo)
+	}
+
+	return created, nil
+}
+
+// markSuppressed records the active same-asset schedules that sch suppresses and
+// that are due within its suppression window, and returns those that were not
+// already suppressed by another schedule.
+func (s *PMService) markSuppressed(sch *model.PMSchedule, byID map[string]*model.PMSchedule, suppressedBy map[string]*model.PMSchedule, now time.Time) []*model.PMSchedule {
+	window := sch.SuppressionWindow()
+	var marked []*model.PMSchedule
+	for _, id := range sch.SuppressPMIDs {
+		target, ok := byID[id]
+		if !ok || target.ID == sch.ID || target.AssetID != sch.AssetID {
+			continue
+		}
+		if _, already := suppressedBy[id]; already {
+			continue
+		}
+		if !target.CheckDue(now).DueWithin(window) {
+			continue
+		}
+		suppressedBy[id] = sch
+		marked = append(marked, target)
+	}
+	return marked
+}
+
+// suppressionOrder returns the indexes of schedules in an order where every schedule
+// comes before the schedules it suppresses on the same asset (a topological order
+// of the suppression graph), so the bigger service fires first. Unrelated
+// schedules keep their relative order; schedules in a suppression cycle are
+// appended in their original order.
+func suppressionOrder(schedules []*model.PMSchedule) []int {
+	index := make(map[string]int, len(schedules))
+	for i, sch := range schedules {
+		index[sch.ID] = i
+	}
+
+	indegree := make([]int, len(schedules))
+	edges := make([][]int, len(schedules))
+	for i, sch := range schedules {
+		for _, id := range sch.SuppressPMIDs {
+			j, ok := index[id]
+			if !ok || j == i || schedules[j].AssetID != sch.AssetID {
+				continue
+			}
+			edges[i] = append(edges[i], j)
+			indegree[j]++
+		}
+	}
+
+	order := make([]int, 0, len(schedules))
+	placed := make([]bool, len(schedules))
+	for len(order) < len(schedules) {
+		// The first unplaced schedule nothing unplaced suppresses, or in a cycle the first unplaced one
+		next := -1
+		for i := range schedules {
+			if !placed[i] && indegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			for i := range schedules {
+				if !placed[i] {
+					next = i
+					break
+				}
+			}
+		}
+		placed[next] = true
+		order = append(order, next)
+		for _, j := range edges[next] {
+			indegree[j]--
+		}
+	}
+	return order
+}
+
+// raiseWorkOrder creates the Preventive_Auto work order for a due schedule and
+// cancels pending work orders of the schedules it suppresses.
+// PM work is planned, so it skips triage and starts out Approved.
+func (s *PMService) raiseWorkOrder(ctx context.Context, sch *model.PMSchedule, status model.PMDueStatus, suppressed []*model.PMSchedule) (*model.WorkOrder, error) {
+	description := describeTriggers(sch, status)
+	suppressedIDs := make([]string, 0, len(suppressed))
+	for _, target := range suppressed {
+		suppressedIDs = append(suppressedIDs, target.ID)
+	}
+
+	wo := &model.WorkOrder{
+		TenantID:            sch.TenantID,
+		AssetID:             &sch.AssetID,
+		PMScheduleID:        &sch.ID,
+		ChecklistTemplateID: sch.ChecklistTemplateID,
+		Status:              model.WOStatusApproved,
+		Origin:              model.WOOriginPreventiveAuto,
+		Priority:            model.WOPriorityMedium,
+		Title:               sch.Title,
+		Description:         &description,
+		SuppressedPMIDs:     suppressedIDs,
+		AssetName:           sch.AssetName,
+	}
+
+	if err := s.woRepo.Create(ctx, wo); err != nil {
+		return nil, err
+	}
+
+	notes := s.suppress(ctx, wo, sch, suppressed)
+	if len(notes) > 0 {
+		description += "\n\nSuppresses (covered by this service):\n- " + strings.Join(notes, "\n- ")
+		wo.Description = &description
+		if err := s.woRepo.Update(ctx, wo, ""); err != nil {
+			slog.Error("Failed to record PM suppression on work order",
+				slog.String("work_order_id", wo.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	s.audit.LogSystem(ctx, sch.TenantID, model.AuditActionCreate, model.AuditEntityWorkOrder, wo.ID, map[string]interface{}{
+		"origin":          wo.Origin,
+		"pmScheduleId":    sch.ID,
+		"triggers":        status.Triggers,
+		"suppressedPmIds": suppressedIDs,
+	})
+
+	return wo, nil
+}
+
+// suppress cancels not-yet-started work orders of the suppressed schedules and
+// returns a human-readable note per suppressed schedule.
+func (s *PMService) suppress(ctx context.Context, wo *model.WorkOrder, sch *model.PMSchedule, suppressed []*model.PMSchedule) []string {
+	var notes []string
+	for _, target := range suppressed {
+		note := target.Title
+		if target.OpenWorkOrderID != nil {
+			reason := fmt.Sprintf("Suppressed by PM \"%s\" (work order #%d).", sch.Title, derefInt(wo.ReadableID))
+			cancelled, err := s.woRepo.CancelSuppressed(ctx, *target.OpenWorkOrderID, reason)
+			if err != nil {
+				slog.Error("Failed to cancel suppressed PM work order",
+					slog.String("work_order_id", *target.OpenWorkOrderID),
+					slog.String("error", err.Error()),
+				)
+			}
+			if cancelled {
+				note += " (pending work order cancelled)"
+				s.audit.LogSystem(ctx, sch.TenantID, model.AuditActionStatusChange, model.AuditEntityWorkOrder, *target.OpenWorkOrderID, map[string]interface{}{
+					"to":           model.WOStatusCancelled,
+					"reason":       "pm_suppressed",
+					"suppressedBy": wo.ID,
+				})
+			} else {
+				note += " (existing work order already underway)"
+			}
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+func derefInt(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// describeTriggers explains which thresholds made the schedule due
+func describeTriggers(sch *model.PMSchedule, status model.PMDueStatus) string {
+	var reasons []string
+	for _, trigger := range status.Triggers {
+		switch trigger {
+		case model.PMTriggerCalendar:
+			if sch.LastPerformedDate == nil {
+				reasons = append(reasons, "calendar (never performed)")
+			} else {
+				reasons = append(reasons, fmt.Sprintf("calendar (%d day interval since %s)",
+					*sch.IntervalDays, sch.LastPerformedDate.Format("2006-01-02")))
+			}
+		case model.PMTriggerRunHours:
+			reasons = append(reasons, fmt.Sprintf("run hours (%.1f h reading, %d h interval)",
+				sch.CurrentRunHours, *sch.IntervalRunHours))
+		case model.PMTriggerOdometer:
+			reasons = append(reasons, fmt.Sprintf("odometer (%.1f km reading, %d km interval)",
+				sch.CurrentOdometerKm, *sch.IntervalOdometerKm))
+		}
+	}
+	return "Auto-generated by PM schedule. Triggered by: " + strings.Join(reasons, ", ")
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+// SLAService escalates work orders that breach their SLA deadlines
+type SLAService struct {
+	woRepo        *repository.WorkOrderRepository
+	notifications *repository.NotificationRepository
+	audit         *AuditService
+}
+
+// NewSLAService creates a new SLA service
+func NewSLAService(woRepo *repository.WorkOrderRepository, notifications *repository.NotificationRepository, audit *AuditService) *SLAService {
+	return &SLAService{woRepo: woRepo, notifications: notifications, audit: audit}
+}
+
+// Run sweeps all tenants' work orders every interval until ctx is cancelled.
+// A non-positive interval disables the sweep.
+func (s *SLAService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		slog.Info("SLA sweep disabled")
+		return
+	}
+
+	slog.Info("SLA sweep started", slog.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		escalated, err := s.Sweep(ctx, "")
+		if err != nil && ctx.Err() == nil {
+			slog.Error("SLA sweep failed", slog.String("error", err.Error()))
+		} else if len(escalated) > 0 {
+			slog.Info("SLA sweep escalated work orders", slog.Int("count", len(escalated)))
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("SLA sweep stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep escalates every open work order (optionally for a single tenant) that has
+// breached a deadline: its priority goes up one level, the tenant's Supervisors
+// are notified and the escalation is audited. Each breach escalates once.
+func (s *SLAService) Sweep(ctx context.Context, tenantID string) ([]model.WorkOrder, error) {
+	breaches, err := s.woRepo.ListSLABreaches(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var escalated []model.WorkOrder
+	for _, wo := range breaches {
+		if ctx.Err() != nil {
+			return escalated, ctx.Err()
+		}
+
+		level, breach, deadline := model.SLAEscalationResponse, "response", wo.RespondBy
+		if wo.ResolveBy != nil && wo.ResolveBy.Before(time.Now()) && wo.CompletedAt == nil {
+			level, breach, deadline = model.SLAEscalationResolution, "resolution", wo.ResolveBy
+		}
+
+		from := wo.Priority
+		wo.Priority = model.EscalatePriority(from)
+
+		applied, err := s.woRepo.Escalate(ctx, &wo, level)
+		if err != nil {
+			slog.Error("Failed to escalate work order",
+				slog.String("workOrderId", wo.ID), slog.String("error", err.Error()))
+			continue
+		}
+		if !applied {
+			continue
+		}
+		wo.EscalationLevel = level
+
+		workOrderID := wo.ID
+		_, err = s.notifications.CreateForRole(ctx, wo.TenantID, middleware.RoleSupervisor, &model.Notification{
+			Kind:        model.NotificationKindSLABreach,
+			WorkOrderID: &workOrderID,
+			Message:     slaBreachMessage(&wo, breach, from),
+		})
+		if err != nil {
+			slog.Error("Failed to notify supervisors of SLA breach",
+				slog.String("workOrderId", wo.ID), slog.String("error", err.Error()))
+		}
+
+		s.audit.LogSystem(ctx, wo.TenantID, model.AuditActionEscalate, model.AuditEntityWorkOrder, wo.ID, map[string]interface{}{
+			"breach":   breach,
+			"deadline": deadline,
+			"priority": map[string]string{"from": from, "to": wo.Priority},
+		})
+
+		escalated = append(escalated, wo)
+	}
+
+	return escalated, nil
+}
+
+// slaBreachMessage describes an escalated work order for its notification
+func slaBreachMessage(wo *model.WorkOrder, breach, fromPriority string) string {
+	ref := wo.Title
+	if wo.ReadableID != nil {
+		ref = fmt.Sprintf("WO-%d %s", *wo.ReadableID, wo.Title)
+	}
+	if fromPriority == wo.Priority {
+		return fmt.Sprintf("%s breached its %s SLA (priority %s)", ref, breach, wo.Priority)
+	}
+	return fmt.Sprintf("%s breached its %s SLA; priority raised from %s to %s", ref, breach, fromPriority, wo.Priority)
+}
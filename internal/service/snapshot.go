@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"ioi-amms/internal/repository"
+)
+
+// snapshotBackfillDays is how far back missing daily snapshots are filled in, so
+// new tenants (and gaps after downtime) start with history
+const snapshotBackfillDays = 90
+
+// SnapshotService captures the nightly KPI snapshots behind the dashboard trends
+type SnapshotService struct {
+	analytics *repository.AnalyticsRepository
+}
+
+// NewSnapshotService creates a new snapshot service
+func NewSnapshotService(analytics *repository.AnalyticsRepository) *SnapshotService {
+	return &SnapshotService{analytics: analytics}
+}
+
+// Run captures missing snapshots every interval until ctx is cancelled. Each run is
+// cheap once a day is captured, so the interval only bounds how soon after
+// midnight (UTC) yesterday's snapshot appears. A non-positive interval disables it.
+func (s *SnapshotService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		slog.Info("Analytics snapshots disabled")
+		return
+	}
+
+	slog.Info("Analytics snapshots started", slog.Duration("interval", interval))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		written, err := s.Capture(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			slog.Error("Analytics snapshot failed", slog.String("error", err.Error()))
+		} else if written > 0 {
+			slog.Info("Analytics snapshots captured", slog.Int("count", written))
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Analytics snapshots stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Capture writes the snapshot of every completed day (UTC) in the backfill window
+// that is still missing, for all tenants
+func (s *SnapshotService) Capture(ctx context.Context, now time.Time) (int, error) {
+	now = now.UTC()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	return s.analytics.CaptureSnapshots(ctx, yesterday.AddDate(0, 0, -(snapshotBackfillDays-1)), yesterday)
+}
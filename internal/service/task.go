@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"ioi-amms/internal/auth"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+var (
+	ErrTaskWorkOrderNotActive = errors.New("tasks can only be recorded while the work order is in progress")
+)
+
+// TaskResultError reports a task result that does not fit the task's type
+type TaskResultError struct {
+	Field   string
+	Message string
+}
+
+func (e *TaskResultError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// TaskResult is the outcome a technician records against a task
+type TaskResult struct {
+	ResultValue *string `json:"resultValue"`
+	ResultNotes *string `json:"resultNotes"`
+	PhotoURL    *string `json:"photoUrl"`
+}
+
+// TaskService records the execution of work order tasks
+type TaskService struct {
+	repo   *repository.TaskRepository
+	woRepo *repository.WorkOrderRepository
+	audit  *AuditService
+}
+
+// NewTaskService creates a new task service
+func NewTaskService(repo *repository.TaskRepository, woRepo *repository.WorkOrderRepository, audit *AuditService) *TaskService {
+	return &TaskService{repo: repo, woRepo: woRepo, audit: audit}
+}
+
+// List returns a work order's tasks in execution order
+func (s *TaskService) List(ctx context.Context, claims *auth.Claims, workOrderID string) ([]model.WOTask, error) {
+	if _, err := s.workOrderForTenant(ctx, claims, workOrderID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByWorkOrder(ctx, workOrderID)
+}
+
+// Complete validates and records a task result on behalf of the user. Recording
+// again overwrites the previous result, so mistakes can be corrected until the
+// work order leaves In_Progress. A failed result raises a defect follow-up.
+func (s *TaskService) Complete(ctx context.Context, claims *auth.Claims, workOrderID, taskID string, result TaskResult) (*model.WOTask, error) {
+	wo, err := s.workOrderForTenant(ctx, claims, workOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if wo.Status != model.WOStatusInProgress {
+		return nil, ErrTaskWorkOrderNotActive
+	}
+
+	task, err := s.repo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.WorkOrderID != wo.ID {
+		return nil, repository.ErrTaskNotFound
+	}
+
+	value, err := validateTaskResult(task, result)
+	if err != nil {
+		return nil, err
+	}
+
+	task.ResultValue = value
+	task.ResultNotes = result.ResultNotes
+	task.PhotoURL = trimmed(result.PhotoURL)
+	task.CompletedByUserID = &claims.UserID
+
+	if err := s.repo.SaveResult(ctx, task); err != nil {
+		return nil, err
+	}
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionUpdate, model.AuditEntityWorkOrder, wo.ID, map[string]interface{}{
+		"taskId":      task.ID,
+		"task":        task.Description,
+		"resultValue": task.ResultValue,
+		"failed":      task.Failed,
+	})
+
+	if task.Failed && task.FollowUpWorkOrderID == nil {
+		followUp, err := s.raiseDefect(ctx, claims, wo, task)
+		if err != nil {
+			slog.Error("Failed to raise defect follow-up",
+				slog.String("task_id", task.ID),
+				slog.String("error", err.Error()),
+			)
+		} else if followUp != nil {
+			task.FollowUpWorkOrderID = &followUp.ID
+		}
+	}
+
+	return task, nil
+}
+
+// raiseDefect spawns a Defect_Followup work order for a failed task so the issue is
+// tracked beyond the current job. It targets the same asset, links back to the parent
+// work order and task, and enters triage as Requested. Returns nil if one already exists.
+func (s *TaskService) raiseDefect(ctx context.Context, claims *auth.Claims, parent *model.WorkOrder, task *model.WOTask) (*model.WorkOrder, error) {
+	description := fmt.Sprintf("Raised from work order #%d (%s): task \"%s\" failed with result %s.",
+		derefInt(parent.ReadableID), parent.Title, task.Description, *task.ResultValue)
+	if task.MinValue != nil || task.MaxValue != nil {
+		description += " Limits: " + formatLimits(task.MinValue, task.MaxValue) + "."
+	}
+	if task.ResultNotes != nil {
+		description += "\n\nTechnician notes: " + *task.ResultNotes
+	}
+
+	wo := &model.WorkOrder{
+		TenantID:          parent.TenantID,
+		AssetID:           parent.AssetID,
+		RequestedByUserID: &claims.UserID,
+		ParentWorkOrderID: &parent.ID,
+		SourceTaskID:      &task.ID,
+		Status:            model.WOStatusRequested,
+		Origin:            model.WOOriginDefectFollowup,
+		Priority:          model.DefectPriority(parent.Priority, task.IsMandatory),
+		Title:             "Defect: " + task.Description,
+		Description:       &description,
+		AssetName:         parent.AssetName,
+	}
+
+	if err := s.woRepo.Create(ctx, wo); err != nil {
+		if errors.Is(err, repository.ErrDefectFollowupExists) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionCreate, model.AuditEntityWorkOrder, wo.ID, map[string]interface{}{
+		"origin":            wo.Origin,
+		"parentWorkOrderId": parent.ID,
+		"sourceTaskId":      task.ID,
+		"priority":          wo.Priority,
+	})
+
+	return wo, nil
+}
+
+// formatLimits renders a task's numeric range, e.g. "10 to 20" or "max 20"
+func formatLimits(min, max *float64) string {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	switch {
+	case min != nil && max != nil:
+		return format(*min) + " to " + format(*max)
+	case min != nil:
+		return "min " + format(*min)
+	default:
+		return "max " + format(*max)
+	}
+}
+
+// workOrderForTenant loads a work order and hides those owned by other tenants
+func (s *TaskService) workOrderForTenant(ctx context.Context, claims *auth.Claims, id string) (*model.WorkOrder, error) {
+	wo, err := s.woRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wo.TenantID != claims.TenantID {
+		return nil, ErrWorkOrderForbidden
+	}
+	return wo, nil
+}
+
+// validateTaskResult checks a result against the task type and returns the normalized value:
+// pass_fail needs Pass or Fail, numeric needs a number (out-of-limit readings are kept and
+// flagged as failed), text needs a value and photo needs a photoUrl.
+func validateTaskResult(task *model.WOTask, result TaskResult) (*string, error) {
+	value := trimmed(result.ResultValue)
+
+	switch task.TaskType {
+	case model.TaskTypePassFail:
+		if value == nil || (*value != model.TaskResultPass && *value != model.TaskResultFail) {
+			return nil, &TaskResultError{Field: "resultValue", Message: "Must be Pass or Fail"}
+		}
+	case model.TaskTypeNumeric:
+		if value == nil {
+			return nil, &TaskResultError{Field: "resultValue", Message: "A numeric reading is required"}
+		}
+		v, err := strconv.ParseFloat(*value, 64)
+		if err != nil {
+			return nil, &TaskResultError{Field: "resultValue", Message: "Must be a number"}
+		}
+		normalized := strconv.FormatFloat(v, 'f', -1, 64)
+		value = &normalized
+	case model.TaskTypeText:
+		if value == nil {
+			return nil, &TaskResultError{Field: "resultValue", Message: "A value is required"}
+		}
+	case model.TaskTypePhoto:
+		if trimmed(result.PhotoURL) == nil {
+			return nil, &TaskResultError{Field: "photoUrl", Message: "A photo is required"}
+		}
+	}
+
+	return value, nil
+}
+
+// trimmed returns nil for a missing or blank string, otherwise the trimmed value
+func trimmed(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"ioi-amms/internal/auth"
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+var (
+	ErrInvalidStatus      = errors.New("unknown work order status")
+	ErrInvalidTransition  = errors.New("status transition not allowed")
+	ErrTransitionDenied   = errors.New("insufficient permissions for status transition")
+	ErrWorkOrderForbidden = errors.New("work order belongs to another tenant")
+	ErrMandatoryTasksOpen = errors.New("mandatory tasks are still open")
+	ErrLaborRequired      = errors.New("smart close-out requires at least one labor entry")
+	ErrInvalidAssignee    = errors.New("assignee must be an active user of the tenant")
+	ErrWorkOrderFinished  = errors.New("work order is closed or cancelled")
+)
+
+// CloseoutError reports missing or inconsistent close-out data
+type CloseoutError struct {
+	Field   string
+	Message string
+}
+
+func (e *CloseoutError) Error() string {
+	return "invalid close-out: " + e.Field + ": " + e.Message
+}
+
+// statusEdge identifies a single transition in the work order lifecycle
+type statusEdge struct {
+	From string
+	To   string
+}
+
+// transitionPermissions maps each lifecycle edge to the permission required to take it.
+// Approving, cancelling and QC decisions are supervisory; executing the job is not.
+var transitionPermissions = map[statusEdge]string{
+	{model.WOStatusRequested, model.WOStatusApproved}:      middleware.PermissionWOApprove,
+	{model.WOStatusRequested, model.WOStatusCancelled}:     middleware.PermissionWOAssign,
+	{model.WOStatusApproved, model.WOStatusInProgress}:     middleware.PermissionWOWrite,
+	{model.WOStatusApproved, model.WOStatusCancelled}:      middleware.PermissionWOAssign,
+	{model.WOStatusInProgress, model.WOStatusWorkComplete}: middleware.PermissionWOWrite,
+	{model.WOStatusInProgress, model.WOStatusCancelled}:    middleware.PermissionWOAssign,
+	{model.WOStatusWorkComplete, model.WOStatusClosed}:     middleware.PermissionWOClose,
+	{model.WOStatusWorkComplete, model.WOStatusInProgress}: middleware.PermissionWOClose,
+}
+
+// TransitionError carries the context of a rejected status transition
+type TransitionError struct {
+	Err     error
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	return e.Err.Error() + ": " + e.From + " -> " + e.To
+}
+
+func (e *TransitionError) Unwrap() error {
+	return e.Err
+}
+
+// OpenTasksError blocks Work_Complete while mandatory tasks are outstanding
+type OpenTasksError struct {
+	Open int
+}
+
+func (e *OpenTasksError) Error() string {
+	return fmt.Sprintf("%s: %d open", ErrMandatoryTasksOpen.Error(), e.Open)
+}
+
+func (e *OpenTasksError) Unwrap() error {
+	return ErrMandatoryTasksOpen
+}
+
+// WorkOrderService enforces the work order lifecycle
+type WorkOrderService struct {
+	repo     *repository.WorkOrderRepository
+	tasks    *repository.TaskRepository
+	labor    *repository.LaborRepository
+	failures failureCodeFinder
+	users    *repository.UserRepository
+	downtime *repository.DowntimeRepository
+	tenants  *repository.TenantRepository
+	audit    *AuditService
+}
+
+// failureCodeFinder looks up the failure codes named in close-out data
+type failureCodeFinder interface {
+	FindByID(ctx context.Context, id string) (*model.FailureCode, error)
+}
+
+// NewWorkOrderService creates a new work order service
+func NewWorkOrderService(repo *repository.WorkOrderRepository, tasks *repository.TaskRepository, labor *repository.LaborRepository, failures *repository.FailureCodeRepository, users *repository.UserRepository, downtime *repository.DowntimeRepository, tenants *repository.TenantRepository, audit *AuditService) *WorkOrderService {
+	return &WorkOrderService{repo: repo, tasks: tasks, labor: labor, failures: failures, users: users, downtime: downtime, tenants: tenants, audit: audit}
+}
+
+// Transition moves a work order to a new status on behalf of the given user.
+// It validates the edge against the state machine, checks the permission for
+// that edge, applies the change and records a status_change audit entry.
+// closeout is only used when closing and may be nil otherwise.
+func (s *WorkOrderService) Transition(ctx context.Context, claims *auth.Claims, id, status string, closeout *model.WOCloseout) (*model.WorkOrder, error) {
+	if !model.IsValidWOStatus(status) {
+		return nil, ErrInvalidStatus
+	}
+
+	wo, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if wo.TenantID != claims.TenantID {
+		return nil, ErrWorkOrderForbidden
+	}
+
+	from := wo.Status
+	if !model.CanTransitionWO(from, status) {
+		return nil, &TransitionError{Err: ErrInvalidTransition, From: from, To: status, Allowed: model.WOTransitions[from]}
+	}
+
+	permission := transitionPermissions[statusEdge{from, status}]
+	selfApproved := false
+	if !middleware.HasPermission(claims.Role, permission) {
+		allowed, err := s.canSelfApprove(ctx, claims, wo, permission)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, &TransitionError{Err: ErrTransitionDenied, From: from, To: status, Allowed: model.WOTransitions[from]}
+		}
+		selfApproved = true
+	}
+
+	// Work cannot be reported complete until every mandatory checklist step is done
+	if status == model.WOStatusWorkComplete {
+		open, err := s.tasks.CountOpenMandatory(ctx, wo.ID)
+		if err != nil {
+			return nil, err
+		}
+		if open > 0 {
+			return nil, &OpenTasksError{Open: open}
+		}
+	}
+
+	if status == model.WOStatusClosed {
+		if err := s.checkCloseout(ctx, wo); err != nil {
+			return nil, err
+		}
+		if err := s.applyCloseout(ctx, wo, closeout); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateStatus(ctx, wo, status); err != nil {
+		return nil, err
+	}
+
+	// Completing the work order that took its asset Down puts the asset back in service
+	if status == model.WOStatusWorkComplete {
+		restored, err := s.downtime.RestoreForWorkOrder(ctx, wo)
+		if err != nil {
+			slog.Error("Failed to restore asset after work order completion",
+				slog.String("work_order_id", wo.ID),
+				slog.String("error", err.Error()),
+			)
+		} else if restored {
+			s.audit.Log(ctx, claims.UserID, model.AuditActionStatusChange, model.AuditEntityAsset, *wo.AssetID, map[string]interface{}{
+				"from":        model.AssetStatusDown,
+				"to":          model.AssetStatusActive,
+				"workOrderId": wo.ID,
+			})
+		}
+	}
+
+	changes := map[string]interface{}{
+		"from": from,
+		"to":   status,
+	}
+	if selfApproved {
+		changes["agileSelfApproval"] = true
+	}
+	if status == model.WOStatusClosed && wo.ProblemCodeID != nil {
+		changes["problemCodeId"] = *wo.ProblemCodeID
+		changes["causeCodeId"] = *wo.CauseCodeID
+		changes["remedyCodeId"] = *wo.RemedyCodeID
+	}
+	s.audit.Log(ctx, claims.UserID, model.AuditActionStatusChange, model.AuditEntityWorkOrder, wo.ID, changes)
+
+	return wo, nil
+}
+
+// Assign sets the work order's assignee, or clears it when userID is nil. The
+// assignee must be an active user of the same tenant. Records an assign audit entry.
+func (s *WorkOrderService) Assign(ctx context.Context, claims *auth.Claims, id string, userID *string) (*model.WorkOrder, error) {
+	wo, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if wo.TenantID != claims.TenantID {
+		return nil, ErrWorkOrderForbidden
+	}
+	if wo.Status == model.WOStatusClosed || wo.Status == model.WOStatusCancelled {
+		return nil, ErrWorkOrderFinished
+	}
+
+	if userID != nil && *userID == "" {
+		userID = nil
+	}
+	if userID != nil {
+		user, err := s.users.FindByID(ctx, *userID)
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrInvalidAssignee
+		}
+		if err != nil {
+			return nil, err
+		}
+		if user.TenantID != claims.TenantID || !user.IsActive {
+			return nil, ErrInvalidAssignee
+		}
+	}
+
+	previous := wo.AssignedUserID
+	if err := s.repo.Assign(ctx, wo.ID, userID); err != nil {
+		return nil, err
+	}
+	wo.AssignedUserID = userID
+
+	s.audit.Log(ctx, claims.UserID, model.AuditActionAssign, model.AuditEntityWorkOrder, wo.ID, map[string]interface{}{
+		"from": previous,
+		"to":   userID,
+	})
+
+	return wo, nil
+}
+
+// checkCloseout enforces the tenant's Smart Close-out rule: a work order cannot be
+// closed until someone has logged labor against it.
+func (s *WorkOrderService) checkCloseout(ctx context.Context, wo *model.WorkOrder) error {
+	tenant, err := s.tenants.GetSettings(ctx, wo.TenantID)
+	if err != nil {
+		return err
+	}
+	if !tenant.SmartCloseout() {
+		return nil
+	}
+
+	entries, err := s.labor.Count(ctx, wo.ID)
+	if err != nil {
+		return err
+	}
+	if entries == 0 {
+		return ErrLaborRequired
+	}
+	return nil
+}
+
+// applyCloseout validates the close-out payload and copies it onto the work order.
+// Corrective and defect work must name its failure as Problem > Cause > Remedy;
+// codes are optional for other origins but must still form a valid chain if given.
+func (s *WorkOrderService) applyCloseout(ctx context.Context, wo *model.WorkOrder, co *model.WOCloseout) error {
+	if co == nil {
+		co = &model.WOCloseout{}
+	}
+
+	given := co.ProblemCodeID != nil || co.CauseCodeID != nil || co.RemedyCodeID != nil
+	if !given && !model.RequiresFailureCodes(wo.Origin) {
+		wo.CloseoutNotes = co.Notes
+		return nil
+	}
+
+	levels := []struct {
+		field string
+		id    *string
+		level string
+	}{
+		{"problemCodeId", co.ProblemCodeID, model.FailureLevelProblem},
+		{"causeCodeId", co.CauseCodeID, model.FailureLevelCause},
+		{"remedyCodeId", co.RemedyCodeID, model.FailureLevelRemedy},
+	}
+
+	var parentID *string
+	for _, l := range levels {
+		if l.id == nil || *l.id == "" {
+			if !model.RequiresFailureCodes(wo.Origin) {
+				return &CloseoutError{Field: l.field, Message: "Invalid " + l.level + " code: failure codes must form a complete Problem > Cause > Remedy chain"}
+			}
+			return &CloseoutError{Field: l.field, Message: "A " + l.level + " code is required to close " + wo.Origin + " work orders"}
+		}
+		code, err := s.failures.FindByID(ctx, *l.id)
+		if errors.Is(err, repository.ErrFailureCodeNotFound) || (err == nil && code.TenantID != wo.TenantID) {
+			return &CloseoutError{Field: l.field, Message: "Failure code not found"}
+		}
+		if err != nil {
+			return err
+		}
+		if code.Level != l.level || !code.IsActive {
+			return &CloseoutError{Field: l.field, Message: "Must be an active " + l.level + " code"}
+		}
+		if parentID != nil && (code.ParentID == nil || *code.ParentID != *parentID) {
+			return &CloseoutError{Field: l.field, Message: "Does not belong to the selected " + model.FailureParentLevel[l.level]}
+		}
+		parentID = &code.ID
+	}
+
+	wo.ProblemCodeID = co.ProblemCodeID
+	wo.CauseCodeID = co.CauseCodeID
+	wo.RemedyCodeID = co.RemedyCodeID
+	wo.CloseoutNotes = co.Notes
+	return nil
+}
+
+// canSelfApprove reports whether Agile Mode lets the user take a supervisory
+// transition on this work order. Only the user's own jobs qualify: those
+// assigned to them or raised by them.
+func (s *WorkOrderService) canSelfApprove(ctx context.Context, claims *auth.Claims, wo *model.WorkOrder, permission string) (bool, error) {
+	if !middleware.HasAgilePermission(claims.Role, permission) || !isOwnWorkOrder(wo, claims.UserID) {
+		return false, nil
+	}
+
+	agile, err := s.tenants.IsAgileMode(ctx, claims.TenantID)
+	if err != nil {
+		return false, err
+	}
+	return agile, nil
+}
+
+// isOwnWorkOrder reports whether the work order is assigned to or was raised by the user
+func isOwnWorkOrder(wo *model.WorkOrder, userID string) bool {
+	if wo.AssignedUserID != nil && *wo.AssignedUserID == userID {
+		return true
+	}
+	return wo.RequestedByUserID != nil && *wo.RequestedByUserID == userID
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"ioi-amms/internal/middleware"
+	"ioi-amms/internal/model"
+	"ioi-amms/internal/repository"
+)
+
+func TestTransitionPermissionsCoverLifecycle(t *testing.T) {
+	for from, targets := range model.WOTransitions {
+		for _, to := range targets {
+			if _, ok := transitionPermissions[statusEdge{from, to}]; !ok {
+				t.Errorf("no permission for %s -> %s", from, to)
+			}
+		}
+	}
+	for edge := range transitionPermissions {
+		if !model.CanTransitionWO(edge.From, edge.To) {
+			t.Errorf("permission for %s -> %s, which is not a lifecycle edge", edge.From, edge.To)
+		}
+	}
+}
+
+func TestTransitionPermissionMatrix(t *testing.T) {
+	roles := []string{
+		middleware.RoleTechnician,
+		middleware.RoleSupervisor,
+		middleware.RoleStoreman,
+		middleware.RoleManager,
+		middleware.RoleAdmin,
+		middleware.RoleViewer,
+	}
+
+	tests := []struct {
+		from, to string
+		allowed  []string
+	}{
+		{model.WOStatusRequested, model.WOStatusApproved, []string{middleware.RoleSupervisor, middleware.RoleManager, middleware.RoleAdmin}},
+		{model.WOStatusRequested, model.WOStatusCancelled, []string{middleware.RoleSupervisor, middleware.RoleManager, middleware.RoleAdmin}},
+		{model.WOStatusApproved, model.WOStatusInProgress, []string{middleware.RoleTechnician, middleware.RoleSupervisor, middleware.RoleManager, middleware.RoleAdmin}},
+		{model.WOStatusApproved, model.WOStatusCancelled, []string{middleware.RoleSupervisor, middleware.RoleManager, middleware.RoleAdmin}},
+		{model.WOStatusInProgress, model.WOStatusWorkComplete, []string{middleware.RoleTechnician, middleware.RoleSupervisor, middleware.RoleManager, middleware.RoleAdmin}},
+		{model.WOStatusInProgress, model.WOStatusCancelled, []string{middleware.RoleSupervisor, middleware.RoleManager, middleware.RoleAdmin}},
+		{model.WOStatusWorkComplete, model.WOStatusClosed, []string{middleware.RoleSupervisor, middleware.RoleManager, middleware.RoleAdmin}},
+		{model.WOStatusWorkComplete, model.WOStatusInProgress, []string{middleware.RoleSupervisor, middleware.RoleManager, middleware.RoleAdmin}},
+	}
+
+	for _, tt := range tests {
+		perm := transitionPermissions[statusEdge{tt.from, tt.to}]
+		allowed := map[string]bool{}
+		for _, r := range tt.allowed {
+			allowed[r] = true
+		}
+		for _, role := range roles {
+			if got := middleware.HasPermission(role, perm); got != allowed[role] {
+				t.Errorf("%s: %s -> %s allowed = %v, want %v", role, tt.from, tt.to, got, allowed[role])
+			}
+		}
+	}
+}
+
+// fakeFailureCodes serves failure codes from memory
+type fakeFailureCodes map[string]*model.FailureCode
+
+func (f fakeFailureCodes) FindByID(ctx context.Context, id string) (*model.FailureCode, error) {
+	if id == "broken" {
+		return nil, errBrokenRepo
+	}
+	code, ok := f[id]
+	if !ok {
+		return nil, repository.ErrFailureCodeNotFound
+	}
+	return code, nil
+}
+
+var errBrokenRepo = errors.New("connection reset")
+
+func TestApplyCloseout(t *testing.T) {
+	const tenant = "t1"
+	str := func(s string) *string { return &s }
+	code := func(id, level string, parent *string) *model.FailureCode {
+		return &model.FailureCode{ID: id, TenantID: tenant, Level: level, ParentID: parent, IsActive: true}
+	}
+
+	codes := fakeFailureCodes{
+		"leak":      code("leak", model.FailureLevelProblem, nil),
+		"noise":     code("noise", model.FailureLevelProblem, nil),
+		"seal":      code("seal", model.FailureLevelCause, str("leak")),
+		"bearing":   code("bearing", model.FailureLevelCause, str("noise")),
+		"replace":   code("replace", model.FailureLevelRemedy, str("seal")),
+		"grease":    code("grease", model.FailureLevelRemedy, str("bearing")),
+		"retired":   {ID: "retired", TenantID: tenant, Level: model.FailureLevelRemedy, ParentID: str("seal")},
+		"elsewhere": {ID: "elsewhere", TenantID: "t2", Level: model.FailureLevelProblem, IsActive: true},
+	}
+	s := &WorkOrderService{failures: codes}
+
+	chain := func(problem, cause, remedy string) *model.WOCloseout {
+		return &model.WOCloseout{ProblemCodeID: str(problem), CauseCodeID: str(cause), RemedyCodeID: str(remedy), Notes: str("done")}
+	}
+
+	tests := []struct {
+		name      string
+		origin    string
+		closeout  *model.WOCloseout
+		wantField string // "" = accepted
+		wantMsg   string // prefix of the close-out error message, if checked
+		wantErr   error
+	}{
+		{"preventive without codes", model.WOOriginPreventiveAuto, &model.WOCloseout{Notes: str("done")}, "", "", nil},
+		{"preventive without payload", model.WOOriginPreventiveAuto, nil, "", "", nil},
+		{"corrective without payload", model.WOOriginManualRequest, nil, "problemCodeId", "A Problem code is required", nil},
+		{"defect without codes", model.WOOriginDefectFollowup, &model.WOCloseout{}, "problemCodeId", "", nil},
+		{"valid chain", model.WOOriginManualRequest, chain("leak", "seal", "replace"), "", "", nil},
+		{"valid chain on preventive", model.WOOriginPreventiveAuto, chain("noise", "bearing", "grease"), "", "", nil},
+		{"partial chain on preventive", model.WOOriginPreventiveAuto, &model.WOCloseout{ProblemCodeID: str("leak")}, "causeCodeId", "Invalid Cause code", nil},
+		{"missing remedy", model.WOOriginManualRequest, &model.WOCloseout{ProblemCodeID: str("leak"), CauseCodeID: str("seal")}, "remedyCodeId", "A Remedy code is required", nil},
+		{"empty problem id", model.WOOriginManualRequest, chain("", "seal", "replace"), "problemCodeId", "", nil},
+		{"cause of another problem", model.WOOriginManualRequest, chain("leak", "bearing", "grease"), "causeCodeId", "", nil},
+		{"remedy of another cause", model.WOOriginManualRequest, chain("leak", "seal", "grease"), "remedyCodeId", "", nil},
+		{"wrong level", model.WOOriginManualRequest, chain("seal", "seal", "replace"), "problemCodeId", "", nil},
+		{"inactive remedy", model.WOOriginManualRequest, chain("leak", "seal", "retired"), "remedyCodeId", "", nil},
+		{"unknown code", model.WOOriginManualRequest, chain("leak", "nope", "replace"), "causeCodeId", "", nil},
+		{"other tenant's code", model.WOOriginManualRequest, chain("elsewhere", "seal", "replace"), "problemCodeId", "", nil},
+		{"lookup failure", model.WOOriginManualRequest, chain("broken", "seal", "replace"), "", "", errBrokenRepo},
+	}
+
+	for _, tt := range tests {
+		wo := &model.WorkOrder{ID: "wo1", TenantID: tenant, Origin: tt.origin}
+		err := s.applyCloseout(context.Background(), wo, tt.closeout)
+
+		var ce *CloseoutError
+		switch {
+		case tt.wantErr != nil:
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			}
+		case tt.wantField != "":
+			if !errors.As(err, &ce) || ce.Field != tt.wantField {
+				t.Errorf("%s: err = %v, want close-out error on %s", tt.name, err, tt.wantField)
+			} else if !strings.HasPrefix(ce.Message, tt.wantMsg) {
+				t.Errorf("%s: message %q, want %q...", tt.name, ce.Message, tt.wantMsg)
+			}
+			if wo.ProblemCodeID != nil || wo.CauseCodeID != nil || wo.RemedyCodeID != nil {
+				t.Errorf("%s: rejected close-out codes were copied onto the work order", tt.name)
+			}
+		default:
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+				continue
+			}
+			if tt.closeout != nil && wo.CloseoutNotes != tt.closeout.Notes {
+				t.Errorf("%s: close-out notes not copied", tt.name)
+			}
+			if tt.closeout != nil && wo.RemedyCodeID != tt.closeout.RemedyCodeID {
+				t.Errorf("%s: remedy code not copied", tt.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_work_orders_requested_by;
+
+ALTER TABLE work_orders DROP COLUMN IF EXISTS requested_by_user_id;
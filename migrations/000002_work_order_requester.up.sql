@@ -0,0 +1,7 @@
+-- Migration: 000002_work_order_requester
+-- Tracks who raised a work order so Agile Mode can recognise a technician's own jobs.
+
+ALTER TABLE work_orders
+ADD COLUMN requested_by_user_id UUID REFERENCES users (id);
+
+CREATE INDEX idx_work_orders_requested_by ON work_orders (requested_by_user_id);
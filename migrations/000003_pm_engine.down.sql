@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pm_schedules_asset;
+
+DROP INDEX IF EXISTS idx_work_orders_open_pm;
+
+ALTER TABLE work_orders DROP COLUMN IF EXISTS pm_schedule_id;
@@ -0,0 +1,13 @@
+-- Migration: 000003_pm_engine
+-- Links auto-generated work orders back to the PM schedule that spawned them.
+
+ALTER TABLE work_orders
+ADD COLUMN pm_schedule_id UUID REFERENCES pm_schedules (id) ON DELETE SET NULL;
+
+-- At most one open work order per schedule, so concurrent evaluator runs cannot duplicate jobs
+CREATE UNIQUE INDEX idx_work_orders_open_pm ON work_orders (pm_schedule_id)
+WHERE
+    pm_schedule_id IS NOT NULL
+    AND status NOT IN ('Closed', 'Cancelled');
+
+CREATE INDEX idx_pm_schedules_asset ON pm_schedules (asset_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_wo_tasks_order;
+
+DROP INDEX IF EXISTS idx_checklist_templates_tenant;
+
+ALTER TABLE work_orders DROP COLUMN IF EXISTS checklist_template_id;
@@ -0,0 +1,9 @@
+-- Migration: 000004_checklists
+-- Records which checklist template a work order's tasks were expanded from.
+
+ALTER TABLE work_orders
+ADD COLUMN checklist_template_id UUID REFERENCES checklist_templates (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_checklist_templates_tenant ON checklist_templates (tenant_id);
+
+CREATE INDEX idx_wo_tasks_order ON wo_tasks (work_order_id, sort_order);
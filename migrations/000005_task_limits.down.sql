@@ -0,0 +1 @@
+ALTER TABLE wo_tasks DROP COLUMN IF EXISTS max_value, DROP COLUMN IF EXISTS min_value;
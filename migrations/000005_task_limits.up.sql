@@ -0,0 +1,6 @@
+-- Migration: 000005_task_limits
+-- Acceptable range for numeric readings, copied from the checklist item when tasks are spawned.
+
+ALTER TABLE wo_tasks
+ADD COLUMN min_value DECIMAL(12, 3),
+ADD COLUMN max_value DECIMAL(12, 3);
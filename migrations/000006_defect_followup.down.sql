@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_work_orders_source_task;
+
+DROP INDEX IF EXISTS idx_work_orders_parent;
+
+ALTER TABLE work_orders DROP COLUMN IF EXISTS source_task_id, DROP COLUMN IF EXISTS parent_work_order_id;
@@ -0,0 +1,15 @@
+-- Migration: 000006_defect_followup
+-- Links Defect_Followup work orders to the work order and failed task that raised them.
+
+ALTER TABLE work_orders
+ADD COLUMN parent_work_order_id UUID REFERENCES work_orders (id) ON DELETE SET NULL,
+ADD COLUMN source_task_id UUID REFERENCES wo_tasks (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_work_orders_parent ON work_orders (parent_work_order_id)
+WHERE
+    parent_work_order_id IS NOT NULL;
+
+-- A failed task raises at most one follow-up, even if its result is recorded again
+CREATE UNIQUE INDEX idx_work_orders_source_task ON work_orders (source_task_id)
+WHERE
+    source_task_id IS NOT NULL;
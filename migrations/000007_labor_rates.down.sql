@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_wo_labor_logs_wo;
+
+ALTER TABLE users DROP COLUMN IF EXISTS hourly_rate;
@@ -0,0 +1,6 @@
+-- Migration: 000007_labor_rates
+-- Per-user hourly rate for labor costing. NULL falls back to the tenant's rate settings.
+
+ALTER TABLE users ADD COLUMN hourly_rate DECIMAL(10, 2);
+
+CREATE INDEX idx_wo_labor_logs_wo ON wo_labor_logs (work_order_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_wo_resource_logs_wo;
+
+ALTER TABLE wo_resource_logs
+DROP COLUMN IF EXISTS created_at,
+DROP COLUMN IF EXISTS location_id,
+DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,9 @@
+-- Migration: 000008_part_consumption
+-- Records who consumed a part on a work order, from which warehouse location, and when.
+
+ALTER TABLE wo_resource_logs
+ADD COLUMN user_id UUID REFERENCES users (id),
+ADD COLUMN location_id UUID REFERENCES locations (id),
+ADD COLUMN created_at TIMESTAMP DEFAULT NOW();
+
+CREATE INDEX idx_wo_resource_logs_wo ON wo_resource_logs (work_order_id);
@@ -0,0 +1,7 @@
+ALTER TABLE work_orders
+DROP COLUMN IF EXISTS closeout_notes,
+DROP COLUMN IF EXISTS remedy_code_id,
+DROP COLUMN IF EXISTS cause_code_id,
+DROP COLUMN IF EXISTS problem_code_id;
+
+DROP TABLE IF EXISTS failure_codes;
@@ -0,0 +1,25 @@
+-- Migration: 000009_failure_codes
+-- Tenant-scoped failure code catalog (Problem > Cause > Remedy) and work order close-out data.
+
+CREATE TABLE failure_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    parent_id UUID REFERENCES failure_codes (id),
+    level VARCHAR(20) NOT NULL CHECK (
+        level IN ('Problem', 'Cause', 'Remedy')
+    ),
+    code VARCHAR(50) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_failure_codes_code ON failure_codes (tenant_id, code);
+
+CREATE INDEX idx_failure_codes_parent ON failure_codes (parent_id);
+
+ALTER TABLE work_orders
+ADD COLUMN problem_code_id UUID REFERENCES failure_codes (id),
+ADD COLUMN cause_code_id UUID REFERENCES failure_codes (id),
+ADD COLUMN remedy_code_id UUID REFERENCES failure_codes (id),
+ADD COLUMN closeout_notes TEXT;
@@ -0,0 +1,5 @@
+ALTER TABLE wo_labor_logs DROP COLUMN IF EXISTS created_at;
+
+DROP TABLE IF EXISTS wo_comment_mentions;
+
+DROP TABLE IF EXISTS wo_comments;
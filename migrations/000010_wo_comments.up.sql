@@ -0,0 +1,24 @@
+-- Migration: 000010_wo_comments
+-- Threaded work order comments with @mentions, and labor entry timestamps for the activity timeline.
+
+CREATE TABLE wo_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    work_order_id UUID NOT NULL REFERENCES work_orders (id) ON DELETE CASCADE,
+    parent_comment_id UUID REFERENCES wo_comments (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id),
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    edited_at TIMESTAMP
+);
+
+CREATE INDEX idx_wo_comments_wo ON wo_comments (work_order_id, created_at);
+
+CREATE TABLE wo_comment_mentions (
+    comment_id UUID NOT NULL REFERENCES wo_comments (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id),
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE INDEX idx_wo_comment_mentions_user ON wo_comment_mentions (user_id);
+
+ALTER TABLE wo_labor_logs ADD COLUMN created_at TIMESTAMP DEFAULT NOW();
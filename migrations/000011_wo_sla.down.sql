@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS notifications;
+
+DROP INDEX IF EXISTS idx_work_orders_sla;
+
+ALTER TABLE work_orders
+DROP COLUMN IF EXISTS escalated_at,
+DROP COLUMN IF EXISTS escalation_level,
+DROP COLUMN IF EXISTS resolve_by,
+DROP COLUMN IF EXISTS respond_by;
@@ -0,0 +1,23 @@
+-- Migration: 000011_wo_sla
+-- Work order SLA deadlines and escalation state, and in-app notifications.
+
+ALTER TABLE work_orders
+ADD COLUMN respond_by TIMESTAMP,
+ADD COLUMN resolve_by TIMESTAMP,
+ADD COLUMN escalation_level INT NOT NULL DEFAULT 0,
+ADD COLUMN escalated_at TIMESTAMP;
+
+CREATE INDEX idx_work_orders_sla ON work_orders (tenant_id, status, resolve_by);
+
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    user_id UUID NOT NULL REFERENCES users (id),
+    kind VARCHAR(50) NOT NULL,
+    work_order_id UUID REFERENCES work_orders (id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    read_at TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC);
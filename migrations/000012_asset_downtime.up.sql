@@ -0,0 +1,25 @@
+-- Migration: 000012_asset_downtime
+-- Asset downtime events: one row per spell in Down / Red_Tag, linked to the causing work order.
+
+CREATE TABLE asset_downtime (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    asset_id UUID NOT NULL REFERENCES assets (id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL,
+    work_order_id UUID REFERENCES work_orders (id) ON DELETE SET NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMP
+);
+
+-- At most one open event per asset
+CREATE UNIQUE INDEX idx_asset_downtime_open ON asset_downtime (asset_id)
+WHERE
+    ended_at IS NULL;
+
+CREATE INDEX idx_asset_downtime_period ON asset_downtime (tenant_id, started_at, ended_at);
+
+-- Assets already down start their event from their last update
+INSERT INTO asset_downtime (tenant_id, asset_id, status, started_at)
+SELECT tenant_id, id, status, COALESCE(updated_at, NOW())
+FROM assets
+WHERE status IN ('Down', 'Red_Tag') AND tenant_id IS NOT NULL;
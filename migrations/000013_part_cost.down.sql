@@ -0,0 +1 @@
+ALTER TABLE parts DROP COLUMN IF EXISTS unit_cost;
@@ -0,0 +1,4 @@
+-- Migration: 000013_part_cost
+-- Unit cost on the parts catalog, used to value wallet holdings and consumption.
+
+ALTER TABLE parts ADD COLUMN unit_cost DECIMAL(12, 2);
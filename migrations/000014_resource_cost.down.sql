@@ -0,0 +1 @@
+ALTER TABLE wo_resource_logs DROP COLUMN IF EXISTS unit_cost;
@@ -0,0 +1,9 @@
+-- Migration: 000014_resource_cost
+-- Snapshot of the part's unit cost when consumed, so later price changes don't rewrite history.
+
+ALTER TABLE wo_resource_logs ADD COLUMN unit_cost DECIMAL(12, 2);
+
+UPDATE wo_resource_logs rl
+SET unit_cost = p.unit_cost
+FROM parts p
+WHERE p.id = rl.part_id;
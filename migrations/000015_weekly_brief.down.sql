@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS brief_deliveries;
+DROP TABLE IF EXISTS brief_subscriptions;
@@ -0,0 +1,18 @@
+-- Migration: 000015_weekly_brief
+-- Monday Morning Brief: who receives the weekly PDF summary, and which weeks were delivered.
+
+CREATE TABLE brief_subscriptions (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- One row per user and week; a missing row means the brief is still due
+CREATE TABLE brief_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    week_start DATE NOT NULL,
+    sent_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (user_id, week_start)
+);
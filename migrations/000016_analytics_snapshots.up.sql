@@ -0,0 +1,18 @@
+-- Migration: 000016_analytics_snapshots
+-- Nightly per-tenant KPI snapshots backing the dashboard trends. Rows are written once per
+-- day and never recomputed, so history doesn't shift when records are later edited.
+
+CREATE TABLE analytics_snapshots (
+    tenant_id UUID NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    snapshot_date DATE NOT NULL,
+    wo_opened INT NOT NULL DEFAULT 0,
+    wo_closed INT NOT NULL DEFAULT 0,
+    wo_backlog INT NOT NULL DEFAULT 0,
+    pm_due INT NOT NULL DEFAULT 0,
+    pm_on_time INT NOT NULL DEFAULT 0,
+    labor_cost DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    parts_cost DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    downtime_hours DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, snapshot_date)
+);
@@ -0,0 +1,22 @@
+-- Migration: 000017_dashboard_layouts
+-- KPI dashboard layouts: a tenant's default per role, and each user's own.
+
+CREATE TABLE dashboard_layouts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    tenant_id UUID NOT NULL REFERENCES tenants (id),
+    role VARCHAR(50),
+    user_id UUID REFERENCES users (id) ON DELETE CASCADE,
+    widgets JSONB NOT NULL DEFAULT '[]',
+    updated_by_user_id UUID REFERENCES users (id),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    -- Either a role default or a user's layout
+    CHECK ((role IS NULL) <> (user_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_dashboard_layouts_role ON dashboard_layouts (tenant_id, role)
+WHERE
+    user_id IS NULL;
+
+CREATE UNIQUE INDEX idx_dashboard_layouts_user ON dashboard_layouts (user_id)
+WHERE
+    user_id IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE assets DROP CONSTRAINT IF EXISTS chk_assets_not_own_parent;
+
+DROP INDEX IF EXISTS idx_assets_parent;
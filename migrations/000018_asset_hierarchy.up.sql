@@ -0,0 +1,7 @@
+-- Migration: 000018_asset_hierarchy
+-- Index for walking the asset tree, and no asset may be its own parent.
+
+CREATE INDEX idx_assets_parent ON assets (parent_id);
+
+ALTER TABLE assets
+ADD CONSTRAINT chk_assets_not_own_parent CHECK (parent_id <> id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_asset_movements_asset;
+
+DROP INDEX IF EXISTS idx_asset_movements_shipment;
+
+ALTER TABLE asset_movements DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,15 @@
+-- Migration: 000019_asset_movement_tenant
+-- Tenant scoping and lookups for asset movements (chain of custody).
+
+ALTER TABLE asset_movements ADD COLUMN tenant_id UUID REFERENCES tenants (id);
+
+UPDATE asset_movements m
+SET
+    tenant_id = a.tenant_id
+FROM assets a
+WHERE
+    a.id = m.asset_id;
+
+CREATE INDEX idx_asset_movements_shipment ON asset_movements (tenant_id, shipment_ref);
+
+CREATE INDEX idx_asset_movements_asset ON asset_movements (asset_id, created_at);
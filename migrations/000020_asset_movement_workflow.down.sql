@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_asset_movements_status;
+
+ALTER TABLE asset_movements
+DROP COLUMN IF EXISTS dispute_reason,
+DROP COLUMN IF EXISTS disputed_at,
+DROP COLUMN IF EXISTS disputed_by_user_id,
+DROP COLUMN IF EXISTS review_note,
+DROP COLUMN IF EXISTS reviewed_at,
+DROP COLUMN IF EXISTS reviewed_by_user_id,
+DROP COLUMN IF EXISTS notes;
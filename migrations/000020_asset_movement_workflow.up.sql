@@ -0,0 +1,13 @@
+-- Migration: 000020_asset_movement_workflow
+-- Approval, receipt and dispute details for asset transfers.
+
+ALTER TABLE asset_movements
+ADD COLUMN notes TEXT,
+ADD COLUMN reviewed_by_user_id UUID REFERENCES users (id),
+ADD COLUMN reviewed_at TIMESTAMP,
+ADD COLUMN review_note TEXT,
+ADD COLUMN disputed_by_user_id UUID REFERENCES users (id),
+ADD COLUMN disputed_at TIMESTAMP,
+ADD COLUMN dispute_reason TEXT;
+
+CREATE INDEX idx_asset_movements_status ON asset_movements (tenant_id, status);
@@ -0,0 +1 @@
+ALTER TABLE work_orders DROP COLUMN IF EXISTS suppressed_pm_ids;
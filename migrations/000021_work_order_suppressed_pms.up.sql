@@ -0,0 +1,4 @@
+-- Migration: 000021_work_order_suppressed_pms
+-- The PM schedules a preventive work order covers; their baselines reset when it closes.
+
+ALTER TABLE work_orders ADD COLUMN suppressed_pm_ids JSONB NOT NULL DEFAULT '[]';
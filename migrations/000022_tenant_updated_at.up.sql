@@ -0,0 +1,4 @@
+-- Migration: 000022_tenant_updated_at
+-- Track when a tenant's settings last changed; UpdateSettings stamps it.
+
+ALTER TABLE tenants ADD COLUMN updated_at TIMESTAMP DEFAULT NOW();